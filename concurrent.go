@@ -0,0 +1,78 @@
+package interval
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// ConcurrentIntervalSet is an IntervalSet safe for concurrent use by multiple goroutines.
+//
+// Reads take a shared lock and writes an exclusive one.
+// The covered region is always kept normalized.
+// The zero value is an empty set ready to use.
+type ConcurrentIntervalSet struct {
+	mu  sync.RWMutex
+	set IntervalSet
+}
+
+// NewConcurrentIntervalSet returns a ConcurrentIntervalSet initially covering set.
+func NewConcurrentIntervalSet(set IntervalSet) *ConcurrentIntervalSet {
+	return &ConcurrentIntervalSet{set: set.Normalize()}
+}
+
+// ContainsPoint reports whether n is covered.
+func (c *ConcurrentIntervalSet) ContainsPoint(n int) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.set.ContainsPoint(n)
+}
+
+// Snapshot returns a copy of the current covered region.
+//
+// The copy does not share storage, so the caller may modify it freely.
+func (c *ConcurrentIntervalSet) Snapshot() IntervalSet {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return slices.Clone(c.set)
+}
+
+// Add adds iv to the covered region.
+//
+// Add(iv): set ← set ∪ {iv}
+func (c *ConcurrentIntervalSet) Add(iv IntegerInterval) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.set = c.set.Union(IntervalSet{iv})
+}
+
+// Subtract removes iv from the covered region.
+//
+// Subtract(iv): set ← set − iv
+func (c *ConcurrentIntervalSet) Subtract(iv IntegerInterval) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.set = c.set.Subtract(iv)
+}
+
+// Stream sends the members of the set, in order, on the returned channel from a new goroutine,
+// and closes the channel once all have been sent or ctx is cancelled.
+//
+// The set is copied first, so it may be modified while streaming.
+// Cancelling ctx stops the goroutine even if nobody is receiving, so it never leaks;
+// members not yet received are then dropped.
+func (set IntervalSet) Stream(ctx context.Context) <-chan IntegerInterval {
+	members := slices.Clone(set)
+	ch := make(chan IntegerInterval)
+	go func() {
+		defer close(ch)
+		for _, iv := range members {
+			select {
+			case ch <- iv:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
@@ -0,0 +1,67 @@
+package interval
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestConcurrentIntervalSet(t *testing.T) {
+	var c ConcurrentIntervalSet
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			c.Add(IntegerInterval{i * 10, i*10 + 5})
+			c.Subtract(IntegerInterval{i*10 + 1, i*10 + 2})
+		}()
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				_ = c.ContainsPoint(i * 10)
+				snapshot := c.Snapshot()
+				if len(snapshot) > 0 {
+					snapshot[0] = IntegerInterval{-1, -1} // 内部状態に影響しないこと
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	got := c.Snapshot()
+	if len(got) != 16 || !slices.Equal(got[:2], IntervalSet{{0, 1}, {2, 5}}) {
+		t.Errorf("Snapshot() = %v", got)
+	}
+}
+
+func TestIntervalSet_Stream(t *testing.T) {
+	set := IntervalSet{{5, 6}, {0, 2}, {3, 4}}
+	var got IntervalSet
+	for iv := range set.Stream(context.Background()) {
+		got = append(got, iv)
+	}
+	if !slices.Equal(got, set) {
+		t.Errorf("Stream delivered %v, want %v", got, set)
+	}
+
+	long := make(IntervalSet, 1000)
+	for i := range long {
+		long[i] = IntegerInterval{i, i + 1}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := long.Stream(ctx)
+	for range 2 {
+		<-ch
+	}
+	cancel()
+	// キャンセル後はチャネルが閉じられ、残りのほとんどは届かない
+	received := 2
+	for range ch {
+		received++
+	}
+	if received >= len(long) {
+		t.Errorf("received all %d members despite cancellation", received)
+	}
+}
@@ -0,0 +1,218 @@
+package interval
+
+import (
+	"fmt"
+	"slices"
+)
+
+// PointsToSet collapses integer positions into a set of maximal runs of consecutive integers.
+//
+// The points may be unsorted and contain duplicates.
+// Returns nil for no points.
+//
+// For example:
+//
+//	points = [7, 1, 2, 3, 8, 2]
+//	result = {[1,4), [7,9)}
+//
+// PointsToSet(P) = minimal set S such that ⋃S = P
+func PointsToSet(points []int) IntervalSet {
+	if len(points) == 0 {
+		return nil
+	}
+	sorted := slices.Clone(points)
+	slices.Sort(sorted)
+	sorted = slices.Compact(sorted)
+
+	var result IntervalSet
+	current := IntegerInterval{Start: sorted[0], End: sorted[0] + 1}
+	for _, p := range sorted[1:] {
+		if p == current.End {
+			current.End++
+		} else {
+			result = append(result, current)
+			current = IntegerInterval{Start: p, End: p + 1}
+		}
+	}
+	return append(result, current)
+}
+
+// ClusterPoints sorts points and groups them into clusters in which consecutive points are
+// at most maxGap apart, returning one interval [first, last+1) per cluster.
+//
+// Duplicates are allowed. With maxGap < 1, neighbouring clusters may be adjacent;
+// they are kept as separate members. Returns nil for no points.
+//
+// For example:
+//
+//	points = [10, 1, 3, 2, 11], maxGap = 2
+//	result = {[1,4), [10,12)}
+func ClusterPoints(points []int, maxGap int) IntervalSet {
+	if len(points) == 0 {
+		return nil
+	}
+	sorted := slices.Clone(points)
+	slices.Sort(sorted)
+
+	var result IntervalSet
+	current := IntegerInterval{Start: sorted[0], End: sorted[0] + 1}
+	for _, p := range sorted[1:] {
+		if p-(current.End-1) <= maxGap {
+			current.End = p + 1
+		} else {
+			result = append(result, current)
+			current = IntegerInterval{Start: p, End: p + 1}
+		}
+	}
+	return append(result, current)
+}
+
+// FromMask builds a set from a mask such as "0110010", where the character at index i is
+// '1' if position i is covered and '0' if not. Runs of '1' become intervals.
+//
+// Returns an error naming the offset of the first character that is neither '0' nor '1'.
+//
+// For example:
+//
+//	mask   = "0110010"
+//	result = {[1,3), [5,6)}
+func FromMask(mask string) (IntervalSet, error) {
+	var result IntervalSet
+	for i := range len(mask) {
+		switch mask[i] {
+		case '0':
+		case '1':
+			if n := len(result); n > 0 && result[n-1].End == i {
+				result[n-1].End++
+			} else {
+				result = append(result, IntegerInterval{Start: i, End: i + 1})
+			}
+		default:
+			return nil, fmt.Errorf("invalid mask character %q at offset %d", mask[i], i)
+		}
+	}
+	return result, nil
+}
+
+// FromMatchIndices converts match indices as returned by regexp.FindAllStringIndex
+// into an IntervalSet, one interval per match.
+//
+// Entries that are not a valid [start, end] pair are skipped.
+//
+// For example:
+//
+//	indices = [[0,2], [5,7]]
+//	result  = {[0,2), [5,7)}
+func FromMatchIndices(indices [][]int) IntervalSet {
+	result := make(IntervalSet, 0, len(indices))
+	for _, pair := range indices {
+		if len(pair) < 2 {
+			continue
+		}
+		iv := IntegerInterval{Start: pair[0], End: pair[1]}
+		if !iv.IsValid() || iv.Start < 0 {
+			continue
+		}
+		result = append(result, iv)
+	}
+	return result
+}
+
+// ToMatchIndices converts the set into regexp-style match indices, one [Start, End] pair per interval.
+//
+// ToMatchIndices() = [[Start, End] | iv ∈ set]
+func (set IntervalSet) ToMatchIndices() [][]int {
+	result := make([][]int, len(set))
+	for i, iv := range set {
+		result[i] = []int{iv.Start, iv.End}
+	}
+	return result
+}
+
+// ToOffsetLength converts the set into {Start, Length} pairs, one per interval, in order.
+//
+// ToOffsetLength() = [[Start, End − Start] | iv ∈ set]
+func (set IntervalSet) ToOffsetLength() [][2]int {
+	result := make([][2]int, len(set))
+	for i, iv := range set {
+		result[i] = [2]int{iv.Start, iv.Length()}
+	}
+	return result
+}
+
+// FromOffsetLength converts {offset, length} pairs into an IntervalSet, one interval per pair.
+//
+// Returns an error if any length is negative.
+//
+// For example:
+//
+//	pairs  = [[2,3], [10,0]]
+//	result = {[2,5), [10,10)}
+func FromOffsetLength(pairs [][2]int) (IntervalSet, error) {
+	result := make(IntervalSet, len(pairs))
+	for i, pair := range pairs {
+		if pair[1] < 0 {
+			return nil, fmt.Errorf("pair %d %v has negative length", i, pair)
+		}
+		result[i] = IntegerInterval{Start: pair[0], End: pair[0] + pair[1]}
+	}
+	return result, nil
+}
+
+// TilePattern repeats pattern every period positions across base, clipped to base.
+//
+// Pattern coordinates are relative to base.Start, so copies are placed at
+// offsets base.Start, base.Start + period, base.Start + 2·period, ...
+// Overlapping copies (a pattern wider than period) are merged; the result is normalized.
+// Returns nil if period ≤ 0 or base is empty.
+//
+// For example:
+//
+//	pattern = {[0,1)}, period = 2
+//	base    = [0,5)
+//	result  = {[0,1), [2,3), [4,5)}
+//
+// TilePattern(P, p, base) = base ∩ ⋃ₖ (P + base.Start + k·p)
+func TilePattern(pattern IntervalSet, period int, base IntegerInterval) IntervalSet {
+	pattern = pattern.Normalize()
+	if period <= 0 || base.Length() <= 0 || len(pattern) == 0 {
+		return nil
+	}
+	var tiles IntervalSet
+	for offset := base.Start; offset+pattern[0].Start < base.End; offset += period {
+		for _, iv := range pattern {
+			tile := IntegerInterval{Start: iv.Start + offset, End: iv.End + offset}
+			if clipped, ok := tile.Intersect(base); ok {
+				tiles = append(tiles, clipped)
+			}
+		}
+	}
+	return tiles.Normalize()
+}
+
+// IntervalsFromPredicate evaluates pred on every integer of [lo, hi) and returns the
+// maximal runs where it is true, sorted and normalized.
+//
+// pred is called once per integer in increasing order, so this is O(hi − lo);
+// avoid it for huge ranges. Returns nil if hi ≤ lo or pred is never true.
+//
+// For example:
+//
+//	lo = 0, hi = 6, pred = n%2 == 0
+//	result = {[0,1), [2,3), [4,5)}
+//
+// IntervalsFromPredicate(lo, hi, p) = minimal set S such that ⋃S = { n ∈ [lo,hi) | p(n) }
+func IntervalsFromPredicate(lo, hi int, pred func(n int) bool) IntervalSet {
+	var result IntervalSet
+	for n := lo; n < hi; n++ {
+		if !pred(n) {
+			continue
+		}
+		if k := len(result); k > 0 && result[k-1].End == n {
+			result[k-1].End++
+		} else {
+			result = append(result, IntegerInterval{Start: n, End: n + 1})
+		}
+	}
+	return result
+}
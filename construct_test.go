@@ -0,0 +1,134 @@
+package interval
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestPointsToSet(t *testing.T) {
+	tests := []struct {
+		points []int
+		want   IntervalSet
+	}{
+		{[]int{1, 2, 3, 7, 8}, IntervalSet{{1, 4}, {7, 9}}},
+		{[]int{8, 3, 3, 1, 2, 7, 1}, IntervalSet{{1, 4}, {7, 9}}},
+		{[]int{-2, 0}, IntervalSet{{-2, -1}, {0, 1}}},
+		{nil, nil},
+	}
+	for _, tt := range tests {
+		got := PointsToSet(tt.points)
+		if !slices.Equal(got, tt.want) || (tt.want == nil) != (got == nil) {
+			t.Errorf("PointsToSet(%v) = %v, want %v", tt.points, got, tt.want)
+		}
+	}
+}
+
+func TestClusterPoints(t *testing.T) {
+	tests := []struct {
+		points []int
+		maxGap int
+		want   IntervalSet
+	}{
+		{[]int{1, 2, 3, 10, 11}, 2, IntervalSet{{1, 4}, {10, 12}}},
+		{[]int{0, 2, 4, 50, 6, 8}, 2, IntervalSet{{0, 9}, {50, 51}}},
+		{[]int{5, 1, 9, 3, 7}, 10, IntervalSet{{1, 10}}},
+		{[]int{4, 4, 4}, 0, IntervalSet{{4, 5}}},
+		{[]int{7}, 3, IntervalSet{{7, 8}}},
+		{nil, 3, nil},
+	}
+	for _, tt := range tests {
+		if got := ClusterPoints(tt.points, tt.maxGap); !slices.Equal(got, tt.want) {
+			t.Errorf("ClusterPoints(%v, %d) = %v, want %v", tt.points, tt.maxGap, got, tt.want)
+		}
+	}
+}
+
+func TestFromMask(t *testing.T) {
+	tests := []struct {
+		mask string
+		want IntervalSet
+	}{
+		{"0110010", IntervalSet{{1, 3}, {5, 6}}},
+		{"1100111", IntervalSet{{0, 2}, {4, 7}}},
+		{"0000", nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got, err := FromMask(tt.mask); err != nil || !slices.Equal(got, tt.want) {
+			t.Errorf("FromMask(%q) = %v, %v, want %v", tt.mask, got, err, tt.want)
+		}
+	}
+	if _, err := FromMask("01x1"); err == nil || !strings.Contains(err.Error(), "offset 2") {
+		t.Errorf("FromMask(%q) error = %v, want one naming offset 2", "01x1", err)
+	}
+}
+
+func TestFromMatchIndices(t *testing.T) {
+	text := "a1 b22 c333"
+	indices := regexp.MustCompile(`\d+`).FindAllStringIndex(text, -1)
+	set := FromMatchIndices(indices)
+	if want := (IntervalSet{{1, 2}, {4, 6}, {8, 11}}); !slices.Equal(set, want) {
+		t.Errorf("FromMatchIndices(%v) = %v, want %v", indices, set, want)
+	}
+	if got := set.ToMatchIndices(); !slices.EqualFunc(got, indices, slices.Equal) {
+		t.Errorf("ToMatchIndices() = %v, want %v", got, indices)
+	}
+	if got := FromMatchIndices([][]int{{3}, {4, 2}, {-1, 0}, {0, 0}}); !slices.Equal(got, IntervalSet{{0, 0}}) {
+		t.Errorf("malformed entries not skipped: %v", got)
+	}
+}
+
+func TestOffsetLength(t *testing.T) {
+	set := IntervalSet{{2, 5}, {-3, 0}, {10, 10}}
+	pairs := set.ToOffsetLength()
+	if want := [][2]int{{2, 3}, {-3, 3}, {10, 0}}; !slices.Equal(pairs, want) {
+		t.Errorf("ToOffsetLength() = %v, want %v", pairs, want)
+	}
+	if back, err := FromOffsetLength(pairs); err != nil || !slices.Equal(back, set) {
+		t.Errorf("FromOffsetLength(%v) = %v, %v, want %v", pairs, back, err, set)
+	}
+	if _, err := FromOffsetLength([][2]int{{0, 2}, {5, -1}}); err == nil {
+		t.Error("expected error for negative length")
+	}
+}
+
+func TestTilePattern(t *testing.T) {
+	tests := []struct {
+		pattern IntervalSet
+		period  int
+		base    IntegerInterval
+		want    IntervalSet
+	}{
+		{IntervalSet{{0, 1}}, 2, IntegerInterval{0, 5}, IntervalSet{{0, 1}, {2, 3}, {4, 5}}},
+		{IntervalSet{{1, 3}}, 4, IntegerInterval{0, 10}, IntervalSet{{1, 3}, {5, 7}, {9, 10}}}, // 末尾で切り詰め
+		{IntervalSet{{0, 3}}, 2, IntegerInterval{0, 6}, IntervalSet{{0, 6}}},                   // 重なるタイルは結合
+		{IntervalSet{{0, 1}}, 3, IntegerInterval{10, 17}, IntervalSet{{10, 11}, {13, 14}, {16, 17}}},
+		{IntervalSet{{0, 1}}, 0, IntegerInterval{0, 5}, nil},
+	}
+	for _, tt := range tests {
+		if got := TilePattern(tt.pattern, tt.period, tt.base); !slices.Equal(got, tt.want) {
+			t.Errorf("TilePattern(%v, %d, %v) = %v, want %v", tt.pattern, tt.period, tt.base, got, tt.want)
+		}
+	}
+}
+
+func TestIntervalsFromPredicate(t *testing.T) {
+	tests := []struct {
+		lo, hi int
+		pred   func(n int) bool
+		want   IntervalSet
+	}{
+		{0, 6, func(n int) bool { return n%2 == 0 }, IntervalSet{{0, 1}, {2, 3}, {4, 5}}},
+		{-3, 10, func(n int) bool { return n < 0 || n >= 7 }, IntervalSet{{-3, 0}, {7, 10}}},
+		{2, 8, func(int) bool { return true }, IntervalSet{{2, 8}}},
+		{2, 8, func(int) bool { return false }, nil},
+		{5, 5, func(int) bool { return true }, nil},
+	}
+	for _, tt := range tests {
+		if got := IntervalsFromPredicate(tt.lo, tt.hi, tt.pred); !slices.Equal(got, tt.want) {
+			t.Errorf("IntervalsFromPredicate(%d, %d) = %v, want %v", tt.lo, tt.hi, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package interval
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// CoordinateMap maps positions of one coordinate system to another by piecewise-linear
+// interpolation between anchor pairs, e.g. to carry annotations across document versions.
+//
+// Positions before the first anchor or after the last one are clamped to that anchor.
+// Build one with NewCoordinateMap; the zero value has no anchors and is the identity map.
+type CoordinateMap struct {
+	anchors [][2]int // {src, dst}, src は狭義単調増加、dst は単調非減少
+}
+
+// NewCoordinateMap returns a CoordinateMap through the given {src, dst} anchor pairs.
+//
+// The anchors must be sorted by strictly increasing src, with non-decreasing dst,
+// so that the map preserves order and valid intervals stay valid.
+// Returns an error if there are no anchors or they are out of order.
+func NewCoordinateMap(anchors [][2]int) (CoordinateMap, error) {
+	if len(anchors) == 0 {
+		return CoordinateMap{}, errors.New("no anchors")
+	}
+	for i := 1; i < len(anchors); i++ {
+		if anchors[i][0] <= anchors[i-1][0] || anchors[i][1] < anchors[i-1][1] {
+			return CoordinateMap{}, fmt.Errorf("anchor %d %v out of order after %v", i, anchors[i], anchors[i-1])
+		}
+	}
+	return CoordinateMap{anchors: append([][2]int(nil), anchors...)}, nil
+}
+
+// Map maps pos through the map, interpolating linearly between the surrounding anchors
+// and rounding to the nearest integer (halves away from zero).
+//
+// For example:
+//
+//	anchors = {0,0}, {10,20}, {20,25}
+//	pos = 5 → 10, pos = 15 → 23 (22.5 rounded), pos = 30 → 25
+func (m CoordinateMap) Map(pos int) int {
+	a := m.anchors
+	if len(a) == 0 {
+		return pos
+	}
+	i := sort.Search(len(a), func(i int) bool { return a[i][0] >= pos })
+	switch {
+	case i == 0:
+		return a[0][1]
+	case i == len(a):
+		return a[len(a)-1][1]
+	}
+	lo, hi := a[i-1], a[i]
+	return lerp(lo[1], hi[1], float64(pos-lo[0])/float64(hi[0]-lo[0]))
+}
+
+// MapInterval maps Start and End of iv through the map.
+//
+// MapInterval(iv) = [Map(Start), Map(End))
+func (m CoordinateMap) MapInterval(iv IntegerInterval) IntegerInterval {
+	return IntegerInterval{Start: m.Map(iv.Start), End: m.Map(iv.End)}
+}
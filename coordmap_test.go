@@ -0,0 +1,59 @@
+package interval
+
+import "testing"
+
+func TestCoordinateMap_MapInterval(t *testing.T) {
+	double, err := NewCoordinateMap([][2]int{{0, 0}, {100, 200}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := double.MapInterval(IntegerInterval{3, 7}); got != (IntegerInterval{6, 14}) {
+		t.Errorf("2x MapInterval([3,7)) = %v, want [6,14)", got)
+	}
+
+	// [0,10) は 2 倍、[10,20) は 1/2 倍
+	m, err := NewCoordinateMap([][2]int{{0, 0}, {10, 20}, {20, 25}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		iv, want IntegerInterval
+	}{
+		{IntegerInterval{2, 5}, IntegerInterval{4, 10}},
+		{IntegerInterval{10, 14}, IntegerInterval{20, 22}},
+		{IntegerInterval{5, 15}, IntegerInterval{10, 23}}, // 22.5 → 23
+		{IntegerInterval{-5, 30}, IntegerInterval{0, 25}}, // 両端は固定
+	}
+	for _, tt := range tests {
+		if got := m.MapInterval(tt.iv); got != tt.want {
+			t.Errorf("MapInterval(%v) = %v, want %v", tt.iv, got, tt.want)
+		}
+	}
+
+	// 負の座標でも補間後の値を丸める：−2.5 → −3
+	shifted, err := NewCoordinateMap([][2]int{{0, -5}, {10, 0}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := shifted.MapInterval(IntegerInterval{5, 7}); got != (IntegerInterval{-3, -2}) {
+		t.Errorf("MapInterval([5,7)) = %v, want [-3,-2)", got)
+	}
+
+	for _, bad := range [][][2]int{nil, {{0, 0}, {0, 5}}, {{0, 10}, {5, 3}}} {
+		if _, err := NewCoordinateMap(bad); err == nil {
+			t.Errorf("NewCoordinateMap(%v) = nil error", bad)
+		}
+	}
+}
+
+func TestCoordinateMap_ZeroValue(t *testing.T) {
+	var m CoordinateMap
+	for _, pos := range []int{-7, 0, 42} {
+		if got := m.Map(pos); got != pos {
+			t.Errorf("zero CoordinateMap.Map(%d) = %d, want identity", pos, got)
+		}
+	}
+	if iv := (IntegerInterval{3, 9}); m.MapInterval(iv) != iv {
+		t.Errorf("zero CoordinateMap.MapInterval(%v) = %v, want identity", iv, m.MapInterval(iv))
+	}
+}
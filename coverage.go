@@ -0,0 +1,265 @@
+package interval
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Event is a boundary of an interval seen by a sweep line:
+// Delta is +1 where an interval starts and −1 where one ends.
+type Event struct {
+	Pos   int
+	Delta int
+}
+
+// Events returns the Start (+1) and End (−1) events of every non-empty interval of the set,
+// sorted by position.
+//
+// At the same position, ends come before starts, so adjacent intervals never count as overlapping.
+// Summing Delta over the events at or before x gives the coverage depth on [x, next event).
+//
+// For example:
+//
+//	set    = {[0,2), [2,5), [1,3)}
+//	result = {0,+1}, {1,+1}, {2,−1}, {2,+1}, {3,−1}, {5,−1}
+func (set IntervalSet) Events() []Event {
+	events := make([]Event, 0, 2*len(set))
+	for _, iv := range set {
+		if iv.Length() > 0 {
+			events = append(events, Event{Pos: iv.Start, Delta: +1}, Event{Pos: iv.End, Delta: -1})
+		}
+	}
+	slices.SortFunc(events, func(a, b Event) int {
+		return cmp.Or(cmp.Compare(a.Pos, b.Pos), cmp.Compare(a.Delta, b.Delta))
+	})
+	return events
+}
+
+// CoverageAtLeast returns the region covered by at least k of the given sets.
+//
+// Each set counts once per position, however many of its own intervals overlap there.
+// k ≤ 1 gives UnionAll and k = len(sets) gives IntersectAll.
+// The result is normalized.
+//
+// For example:
+//
+//	sets   = {[0,6)}, {[2,8)}, {[4,10)}
+//	k = 2 → {[2,8)}
+//
+// CoverageAtLeast(k, S₁, …, Sₙ) = { x | #{i | x ∈ Sᵢ} ≥ k }
+func CoverageAtLeast(k int, sets ...IntervalSet) IntervalSet {
+	var all IntervalSet
+	for _, set := range sets {
+		all = append(all, set.Normalize()...)
+	}
+	return depthRegions(all.Events(), func(depth int) bool {
+		return depth >= max(k, 1)
+	})
+}
+
+// CountedInterval is a region together with how many input sets cover it.
+type CountedInterval struct {
+	Interval IntegerInterval
+	Count    int
+}
+
+// AccumulateCoverage sweeps all sets together and returns the maximal regions covered by
+// at least one of them, each labeled with the number of sets covering it, in coordinate order.
+//
+// Each set counts once per position, however many of its own intervals overlap there.
+// Neighbouring regions always differ in Count, and uncovered gaps are omitted.
+//
+// For example:
+//
+//	sets   = {[0,6)}, {[4,10)}
+//	result = {[0,4),1}, {[4,6),2}, {[6,10),1}
+//
+// AccumulateCoverage(S₁, …, Sₙ) labels x with #{i | x ∈ Sᵢ}
+func AccumulateCoverage(sets ...IntervalSet) []CountedInterval {
+	var all IntervalSet
+	for _, set := range sets {
+		all = append(all, set.Normalize()...)
+	}
+	events := all.Events()
+	var result []CountedInterval
+	depth := 0
+	for i, e := range events {
+		depth += e.Delta
+		if i+1 == len(events) || events[i+1].Pos == e.Pos || depth == 0 {
+			continue
+		}
+		next := events[i+1].Pos
+		if n := len(result); n > 0 && result[n-1].Interval.End == e.Pos && result[n-1].Count == depth {
+			result[n-1].Interval.End = next
+		} else {
+			result = append(result, CountedInterval{Interval: IntegerInterval{Start: e.Pos, End: next}, Count: depth})
+		}
+	}
+	return result
+}
+
+// FilterByDepth returns the region covered by at least minDepth members of the set.
+//
+// Unlike CoverageAtLeast, every member counts on its own. minDepth ≤ 1 gives the
+// normalized set with empty members dropped. The result is normalized.
+//
+// For example:
+//
+//	set      = {[0,4), [2,6), [3,5)}
+//	minDepth = 2 → {[2,5)}
+//
+// FilterByDepth(k) = { x | #{iv ∈ set | x ∈ iv} ≥ k }
+func (set IntervalSet) FilterByDepth(minDepth int) IntervalSet {
+	return depthRegions(set.Events(), func(depth int) bool {
+		return depth >= max(minDepth, 1)
+	})
+}
+
+// CapDepth clips the members of the set so that no position is covered by more than maxDepth
+// of them, and returns the remaining pieces sorted by position.
+//
+// Members are admitted in order of Start (then End), so at an over-capacity region the member
+// that starts latest yields: it loses the parts where maxDepth earlier members are already
+// present, and is dropped entirely if nothing remains. Since every earlier member starts no later,
+// those parts always form a prefix, so a member is only ever shortened from the left, never split.
+// Empty members are dropped, and maxDepth ≤ 0 gives nil. This takes O(n²) time in the worst case.
+//
+// For example:
+//
+//	set      = {[0,6), [1,5), [2,8)}
+//	maxDepth = 2
+//	result   = {[0,6), [1,5), [5,8)}
+func (set IntervalSet) CapDepth(maxDepth int) IntervalSet {
+	if maxDepth <= 0 {
+		return nil
+	}
+	sorted := slices.DeleteFunc(slices.Clone(set), func(iv IntegerInterval) bool {
+		return iv.Length() <= 0
+	})
+	slices.SortFunc(sorted, IntegerInterval.Compare)
+	var result IntervalSet
+	for _, iv := range sorted {
+		var nearby IntervalSet
+		for _, kept := range result {
+			if kept.Overlaps(iv) {
+				nearby = append(nearby, kept)
+			}
+		}
+		full := nearby.FilterByDepth(maxDepth)
+		result = append(result, full.ComplementIn(IntervalSet{iv})...)
+	}
+	slices.SortFunc(result, IntegerInterval.Compare)
+	return result
+}
+
+// depthRegions はソート済みイベント列を走査し、深さが keep を満たす領域を正規化された集合として返す。
+func depthRegions(events []Event, keep func(depth int) bool) IntervalSet {
+	var result IntervalSet
+	depth := 0
+	for i, e := range events {
+		depth += e.Delta
+		if i+1 == len(events) || events[i+1].Pos == e.Pos || !keep(depth) {
+			continue
+		}
+		next := events[i+1].Pos
+		if n := len(result); n > 0 && result[n-1].End == e.Pos {
+			result[n-1].End = next
+		} else {
+			result = append(result, IntegerInterval{Start: e.Pos, End: next})
+		}
+	}
+	return result
+}
+
+// Transitions walks the normalized set over [0, length) in a single pass, calling onEnter
+// where a covered region starts and onLeave where it ends.
+//
+// Regions are clipped to [0, length), so a region running past length leaves at length.
+// Calls strictly alternate, starting with onEnter, and are in increasing position order.
+//
+// For example:
+//
+//	set = {[0,2), [2,4), [6,12)}, length = 10
+//	onEnter(0), onLeave(4), onEnter(6), onLeave(10)
+func (set IntervalSet) Transitions(length int, onEnter, onLeave func(pos int)) {
+	for _, iv := range intersectNormalized(set.Normalize(), IntervalSet{{Start: 0, End: length}}) {
+		onEnter(iv.Start)
+		onLeave(iv.End)
+	}
+}
+
+// OddCoverage returns the region covered by an odd number of members (the even-odd fill rule).
+//
+// The result is normalized.
+//
+// For example:
+//
+//	set    = {[0,4), [2,6)}
+//	result = {[0,2), [4,6)}
+//
+// OddCoverage() = { x | #{iv ∈ set | x ∈ iv} is odd }
+func (set IntervalSet) OddCoverage() IntervalSet {
+	return depthRegions(set.Events(), func(depth int) bool {
+		return depth%2 == 1
+	})
+}
+
+// MaxOverlapGroup returns the members that all contain the leftmost point of maximum
+// coverage depth, in their original order: a largest group of mutually overlapping intervals.
+//
+// For intervals, a set of pairwise-overlapping members always shares a common point,
+// so the group's size equals the maximum depth. Returns nil if the set covers nothing.
+//
+// For example:
+//
+//	set    = {[0,4), [2,6), [3,8), [7,9)}
+//	result = {[0,4), [2,6), [3,8)}
+func (set IntervalSet) MaxOverlapGroup() IntervalSet {
+	depth, best := 0, 0
+	var at int
+	for _, e := range set.Events() {
+		depth += e.Delta
+		if depth > best {
+			best, at = depth, e.Pos
+		}
+	}
+	if best == 0 {
+		return nil
+	}
+	return set.IntervalsCovering(at)
+}
+
+// DepthRun is a run of Length consecutive positions all covered to the same Depth.
+type DepthRun struct {
+	Length, Depth int
+}
+
+// DepthRuns returns the coverage depth of the set run-length encoded, in coordinate order.
+//
+// The runs start at the smallest Start of a non-empty member and end at the largest End;
+// positions before the first run are implicitly uncovered, so the caller needs that Start
+// to place them. Gaps inside the span appear as runs of depth 0, and consecutive runs of
+// equal depth are coalesced. Returns nil if the set covers nothing.
+//
+// For example:
+//
+//	set    = {[0,3), [2,5)}
+//	result = {2,1}, {1,2}, {2,1}
+func (set IntervalSet) DepthRuns() []DepthRun {
+	events := set.Events()
+	var runs []DepthRun
+	depth := 0
+	for i, e := range events {
+		depth += e.Delta
+		if i+1 == len(events) || events[i+1].Pos == e.Pos {
+			continue
+		}
+		length := events[i+1].Pos - e.Pos
+		if n := len(runs); n > 0 && runs[n-1].Depth == depth {
+			runs[n-1].Length += length
+		} else {
+			runs = append(runs, DepthRun{Length: length, Depth: depth})
+		}
+	}
+	return runs
+}
@@ -0,0 +1,221 @@
+package interval
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestIntervalSet_Events(t *testing.T) {
+	set := IntervalSet{{0, 2}, {2, 5}, {1, 3}, {4, 4}}
+	want := []Event{{0, 1}, {1, 1}, {2, -1}, {2, 1}, {3, -1}, {5, -1}}
+	if got := set.Events(); !slices.Equal(got, want) {
+		t.Errorf("Events() = %v, want %v", got, want)
+	}
+
+	r := rand.New(rand.NewPCG(7, 8))
+	for range 200 {
+		set := randomSet(r)
+		events := set.Events()
+		depth := 0
+		for i, e := range events {
+			depth += e.Delta
+			if depth < 0 {
+				t.Fatalf("%v: negative depth after %v", set, e)
+			}
+			if i+1 < len(events) && events[i+1].Pos == e.Pos {
+				continue
+			}
+			count := 0
+			for _, iv := range set {
+				if iv.Contains(e.Pos) {
+					count++
+				}
+			}
+			if depth != count {
+				t.Fatalf("%v: depth at %d = %d, want %d", set, e.Pos, depth, count)
+			}
+		}
+	}
+}
+
+func TestCoverageAtLeast(t *testing.T) {
+	a := IntervalSet{{0, 6}, {1, 3}}
+	b := IntervalSet{{2, 8}}
+	c := IntervalSet{{4, 10}, {20, 22}}
+	if got, want := CoverageAtLeast(1, a, b, c), UnionAll(a, b, c); !slices.Equal(got, want) {
+		t.Errorf("CoverageAtLeast(1) = %v, want %v", got, want)
+	}
+	if got, want := CoverageAtLeast(2, a, b, c), (IntervalSet{{2, 8}}); !slices.Equal(got, want) {
+		t.Errorf("CoverageAtLeast(2) = %v, want %v", got, want)
+	}
+	if got, want := CoverageAtLeast(3, a, b, c), IntersectAll(a, b, c); !slices.Equal(got, want) {
+		t.Errorf("CoverageAtLeast(3) = %v, want %v", got, want)
+	}
+	if got, want := CoverageAtLeast(2, b, b), (IntervalSet{{2, 8}}); !slices.Equal(got, want) {
+		t.Errorf("CoverageAtLeast(2, b, b) = %v, want %v", got, want)
+	}
+	if got := CoverageAtLeast(4, a, b, c); len(got) != 0 {
+		t.Errorf("CoverageAtLeast(4) = %v, want {}", got)
+	}
+}
+
+func TestAccumulateCoverage(t *testing.T) {
+	a := IntervalSet{{0, 6}, {1, 3}} // a 内の重なりは一度だけ数える
+	b := IntervalSet{{2, 8}}
+	c := IntervalSet{{4, 10}, {20, 22}}
+	got := AccumulateCoverage(a, b, c)
+	want := []CountedInterval{
+		{IntegerInterval{0, 2}, 1},
+		{IntegerInterval{2, 4}, 2},
+		{IntegerInterval{4, 6}, 3},
+		{IntegerInterval{6, 8}, 2},
+		{IntegerInterval{8, 10}, 1},
+		{IntegerInterval{20, 22}, 1},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("AccumulateCoverage = %v, want %v", got, want)
+	}
+	for k := 1; k <= 3; k++ {
+		var atLeast IntervalSet
+		for _, region := range got {
+			if region.Count >= k {
+				atLeast = append(atLeast, region.Interval)
+			}
+		}
+		if !slices.Equal(atLeast.Normalize(), CoverageAtLeast(k, a, b, c)) {
+			t.Errorf("regions with count ≥ %d = %v, want %v", k, atLeast, CoverageAtLeast(k, a, b, c))
+		}
+	}
+	if got := AccumulateCoverage(IntervalSet{{0, 2}}, IntervalSet{{2, 4}}); !slices.Equal(got, []CountedInterval{{IntegerInterval{0, 4}, 1}}) {
+		t.Errorf("adjacent regions of equal count not merged: %v", got)
+	}
+	if got := AccumulateCoverage(); len(got) != 0 {
+		t.Errorf("AccumulateCoverage() = %v, want empty", got)
+	}
+}
+
+func TestIntervalSet_FilterByDepth(t *testing.T) {
+	set := IntervalSet{{0, 4}, {2, 6}, {3, 5}, {8, 9}}
+	tests := []struct {
+		minDepth int
+		want     IntervalSet
+	}{
+		{1, IntervalSet{{0, 6}, {8, 9}}},
+		{2, IntervalSet{{2, 5}}},
+		{3, IntervalSet{{3, 4}}},
+		{4, nil},
+	}
+	for _, tt := range tests {
+		if got := set.FilterByDepth(tt.minDepth); !slices.Equal(got, tt.want) {
+			t.Errorf("FilterByDepth(%d) = %v, want %v", tt.minDepth, got, tt.want)
+		}
+	}
+	r := rand.New(rand.NewPCG(35, 36))
+	for range 200 {
+		set := randomSet(r)
+		if got, want := set.FilterByDepth(1), set.canonical(); !slices.Equal(got, want) {
+			t.Fatalf("%v.FilterByDepth(1) = %v, want %v", set, got, want)
+		}
+	}
+}
+
+func TestIntervalSet_CapDepth(t *testing.T) {
+	tests := []struct {
+		set      IntervalSet
+		maxDepth int
+		want     IntervalSet
+	}{
+		{IntervalSet{{2, 8}, {0, 6}, {1, 5}}, 2, IntervalSet{{0, 6}, {1, 5}, {5, 8}}},
+		// [1,10) は先頭を削られ、その後に始まる [5,8) の 2 つ目は満杯の領域に収まるので捨てられる
+		{IntervalSet{{0, 3}, {0, 3}, {5, 8}, {5, 8}, {1, 10}}, 2, IntervalSet{{0, 3}, {0, 3}, {3, 10}, {5, 8}}},
+		{IntervalSet{{0, 4}, {0, 4}, {1, 3}}, 2, IntervalSet{{0, 4}, {0, 4}}},
+		{IntervalSet{{0, 2}, {2, 4}}, 1, IntervalSet{{0, 2}, {2, 4}}},
+		{IntervalSet{{0, 2}}, 0, nil},
+	}
+	for _, tt := range tests {
+		got := tt.set.CapDepth(tt.maxDepth)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%v.CapDepth(%d) = %v, want %v", tt.set, tt.maxDepth, got, tt.want)
+		}
+	}
+	r := rand.New(rand.NewPCG(37, 38))
+	for range 200 {
+		set, maxDepth := randomSet(r), 1+r.IntN(3)
+		got := set.CapDepth(maxDepth)
+		if len(got.FilterByDepth(maxDepth+1)) != 0 {
+			t.Fatalf("%v.CapDepth(%d) = %v exceeds the cap", set, maxDepth, got)
+		}
+		// 上限 1 以上なら覆われる領域そのものは変わらない
+		if !slices.Equal(got.canonical(), set.canonical()) {
+			t.Fatalf("%v.CapDepth(%d) = %v changes the covered region", set, maxDepth, got)
+		}
+	}
+}
+
+func TestIntervalSet_Transitions(t *testing.T) {
+	set := IntervalSet{{6, 12}, {0, 2}, {2, 4}, {-5, -1}}
+	var log []string
+	set.Transitions(10,
+		func(pos int) { log = append(log, fmt.Sprintf("enter %d", pos)) },
+		func(pos int) { log = append(log, fmt.Sprintf("leave %d", pos)) },
+	)
+	want := []string{"enter 0", "leave 4", "enter 6", "leave 10"}
+	if !slices.Equal(log, want) {
+		t.Errorf("Transitions = %v, want %v", log, want)
+	}
+}
+
+func TestIntervalSet_OddCoverage(t *testing.T) {
+	tests := []struct {
+		set, want IntervalSet
+	}{
+		{IntervalSet{{0, 4}, {2, 6}}, IntervalSet{{0, 2}, {4, 6}}},
+		// 深さ 1,2,3,2,1 → 中央で奇数に戻る
+		{IntervalSet{{0, 10}, {2, 8}, {4, 6}}, IntervalSet{{0, 2}, {4, 6}, {8, 10}}},
+		{IntervalSet{{0, 3}, {0, 3}}, nil},
+		{IntervalSet{{0, 2}, {2, 4}}, IntervalSet{{0, 4}}},
+	}
+	for _, tt := range tests {
+		if got := tt.set.OddCoverage(); !slices.Equal(got, tt.want) {
+			t.Errorf("%v.OddCoverage() = %v, want %v", tt.set, got, tt.want)
+		}
+	}
+}
+
+func TestIntervalSet_MaxOverlapGroup(t *testing.T) {
+	tests := []struct {
+		set, want IntervalSet
+	}{
+		{IntervalSet{{3, 8}, {0, 4}, {7, 9}, {2, 6}}, IntervalSet{{3, 8}, {0, 4}, {2, 6}}},
+		// 接するだけの区間は同じグループに入らない
+		{IntervalSet{{0, 2}, {2, 4}}, IntervalSet{{0, 2}}},
+		{IntervalSet{{1, 1}}, nil},
+		{nil, nil},
+	}
+	for _, tt := range tests {
+		if got := tt.set.MaxOverlapGroup(); !slices.Equal(got, tt.want) {
+			t.Errorf("%v.MaxOverlapGroup() = %v, want %v", tt.set, got, tt.want)
+		}
+	}
+}
+
+func TestIntervalSet_DepthRuns(t *testing.T) {
+	tests := []struct {
+		set  IntervalSet
+		want []DepthRun
+	}{
+		{IntervalSet{{0, 3}, {2, 5}}, []DepthRun{{2, 1}, {1, 2}, {2, 1}}},
+		{IntervalSet{{0, 6}, {2, 5}, {3, 4}}, []DepthRun{{2, 1}, {1, 2}, {1, 3}, {1, 2}, {1, 1}}},
+		// 接する同じ深さの区間はひとつの run になり、隙間は深さ 0 の run になる
+		{IntervalSet{{2, 4}, {4, 6}, {8, 9}}, []DepthRun{{4, 1}, {2, 0}, {1, 1}}},
+		{IntervalSet{{3, 3}}, nil},
+		{nil, nil},
+	}
+	for _, tt := range tests {
+		if got := tt.set.DepthRuns(); !slices.Equal(got, tt.want) {
+			t.Errorf("%v.DepthRuns() = %v, want %v", tt.set, got, tt.want)
+		}
+	}
+}
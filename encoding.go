@@ -0,0 +1,275 @@
+package interval
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Key returns a canonical string for the set, usable as a map key.
+//
+// Two sets covering the same integers have the same key, regardless of
+// order, overlaps, adjacency or empty intervals.
+//
+// For example:
+//
+//	{[2,4), [0,2)} → "{[0,4)}"
+//	{[0,4), [3,3)} → "{[0,4)}"
+//
+// a.Key() = b.Key() ⇔ ⋃a = ⋃b
+func (set IntervalSet) Key() string {
+	return set.canonical().String()
+}
+
+// Hash64 returns a 64-bit FNV-1a hash of the set's canonical form.
+//
+// Like Key, sets covering the same integers hash equally; distinct sets usually,
+// but not necessarily, hash differently. It is not a cryptographic hash.
+func (set IntervalSet) Hash64() uint64 {
+	h := fnv.New64a()
+	var buf [16]byte
+	for _, iv := range set.canonical() {
+		binary.LittleEndian.PutUint64(buf[:8], uint64(iv.Start))
+		binary.LittleEndian.PutUint64(buf[8:], uint64(iv.End))
+		h.Write(buf[:])
+	}
+	return h.Sum64()
+}
+
+// Signature returns a bits-bit fingerprint of the set's coverage of base for approximate matching:
+// base is split into bits cells as in Histogram, and bit i (from the least significant) is set
+// if cell i is at least half covered. Similar sets have signatures at a small Hamming distance
+// (bits.OnesCount64 of the XOR).
+//
+// Returns 0 if bits is not in [1,64] or base is empty.
+//
+// For example:
+//
+//	set  = {[0,6), [12,16)}
+//	base = [0,16), bits = 4 → 0b1011
+func (set IntervalSet) Signature(base IntegerInterval, bits int) uint64 {
+	if bits < 1 || bits > 64 {
+		return 0
+	}
+	width := base.Length() / bits
+	var signature uint64
+	for i, covered := range set.Histogram(base, bits) {
+		cellWidth := width
+		if i == bits-1 {
+			cellWidth = base.End - (base.Start + i*width)
+		}
+		if cellWidth > 0 && 2*covered >= cellWidth {
+			signature |= 1 << i
+		}
+	}
+	return signature
+}
+
+// ParseRangeList parses a comma-separated list of inclusive ranges such as "0-3,5,7-9".
+//
+// "a-b" means a..b inclusive, i.e. [a, b+1), and a bare "n" means [n, n+1).
+// Whitespace around tokens is ignored and the result is normalized.
+// An empty string yields nil. Returns an error naming the offending token
+// for a reversed range or a non-numeric token.
+//
+// For example:
+//
+//	"0-3,5,7-9" → {[0,4), [5,6), [7,10)}
+func ParseRangeList(s string) (IntervalSet, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	var result IntervalSet
+	for _, token := range strings.Split(s, ",") {
+		token = strings.TrimSpace(token)
+		lo, hi := token, token
+		// 先頭の '-' は負の数の符号
+		if i := strings.Index(token[min(1, len(token)):], "-"); i >= 0 {
+			lo, hi = token[:i+1], token[i+2:]
+		}
+		a, errA := strconv.Atoi(strings.TrimSpace(lo))
+		b, errB := strconv.Atoi(strings.TrimSpace(hi))
+		if errA != nil || errB != nil {
+			return nil, fmt.Errorf("invalid range %q", token)
+		}
+		if a > b {
+			return nil, fmt.Errorf("reversed range %q", token)
+		}
+		result = append(result, IntegerInterval{Start: a, End: b + 1})
+	}
+	return result.Normalize(), nil
+}
+
+// ToRangeList formats the set as an inclusive range list accepted by ParseRangeList.
+//
+// The set is normalized first, so consecutive integers collapse into one range.
+//
+// For example:
+//
+//	{[0,4), [5,6), [7,10)} → "0-3,5,7-9"
+func (set IntervalSet) ToRangeList() string {
+	canonical := set.canonical()
+	parts := make([]string, len(canonical))
+	for i, iv := range canonical {
+		if iv.Length() == 1 {
+			parts[i] = strconv.Itoa(iv.Start)
+		} else {
+			parts[i] = strconv.Itoa(iv.Start) + "-" + strconv.Itoa(iv.End-1)
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// GobEncode implements gob.GobEncoder.
+//
+// The set is encoded as a flat []int of Start, End pairs, which avoids the per-field
+// overhead of gob's default struct encoding. Order and duplicates are preserved.
+func (set IntervalSet) GobEncode() ([]byte, error) {
+	flat := make([]int, 0, 2*len(set))
+	for _, iv := range set {
+		flat = append(flat, iv.Start, iv.End)
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(flat); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GobDecode implements gob.GobDecoder, decoding the format written by GobEncode.
+func (set *IntervalSet) GobDecode(data []byte) error {
+	var flat []int
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&flat); err != nil {
+		return err
+	}
+	if len(flat)%2 != 0 {
+		return fmt.Errorf("odd number of endpoints: %d", len(flat))
+	}
+	decoded := make(IntervalSet, len(flat)/2)
+	for i := range decoded {
+		decoded[i] = IntegerInterval{Start: flat[2*i], End: flat[2*i+1]}
+	}
+	*set = decoded
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler with a compact varint format.
+//
+// The set is normalized (empty intervals dropped) before encoding, so only the covered
+// integers survive a round trip. The stream is the interval count, the first Start,
+// then for each interval its length followed by the gap to the next Start.
+// Sorted, nearby intervals therefore encode in a few bytes each.
+func (set IntervalSet) MarshalBinary() ([]byte, error) {
+	canonical := set.canonical()
+	buf := binary.AppendUvarint(nil, uint64(len(canonical)))
+	for i, iv := range canonical {
+		if i == 0 {
+			buf = binary.AppendVarint(buf, int64(iv.Start))
+		} else {
+			buf = binary.AppendUvarint(buf, uint64(iv.Start-canonical[i-1].End))
+		}
+		buf = binary.AppendUvarint(buf, uint64(iv.Length()))
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, decoding the format written by MarshalBinary.
+func (set *IntervalSet) UnmarshalBinary(data []byte) error {
+	errCorrupt := errors.New("corrupt binary interval set")
+	readUvarint := func() (int, error) {
+		v, n := binary.Uvarint(data)
+		if n <= 0 {
+			return 0, errCorrupt
+		}
+		data = data[n:]
+		return int(v), nil
+	}
+
+	count, err := readUvarint()
+	if err != nil || count > len(data) {
+		return errCorrupt
+	}
+	decoded := make(IntervalSet, 0, count)
+	pos := 0
+	for i := range count {
+		if i == 0 {
+			start, n := binary.Varint(data)
+			if n <= 0 {
+				return errCorrupt
+			}
+			data = data[n:]
+			pos = int(start)
+		} else {
+			gap, err := readUvarint()
+			if err != nil {
+				return err
+			}
+			pos += gap
+		}
+		length, err := readUvarint()
+		if err != nil {
+			return err
+		}
+		decoded = append(decoded, IntegerInterval{Start: pos, End: pos + length})
+		pos += length
+	}
+	if len(data) != 0 {
+		return errCorrupt
+	}
+	*set = decoded
+	return nil
+}
+
+// canonical は正規化した上で空区間を取り除いた、被覆する整数だけで決まる表現を返す。
+func (set IntervalSet) canonical() IntervalSet {
+	return slices.DeleteFunc(set.Normalize(), func(iv IntegerInterval) bool {
+		return iv.Length() <= 0
+	})
+}
+
+// Togglepoints returns the positions where coverage flips, in increasing order:
+// the Start and End of every normalized member.
+//
+// Adjacent members merge, so they contribute no interior toggle.
+// Returns nil for an empty set.
+//
+// For example:
+//
+//	{[0,2), [5,7)} → [0, 2, 5, 7]
+func (set IntervalSet) Togglepoints() []int {
+	var points []int
+	for _, iv := range set.canonical() {
+		points = append(points, iv.Start, iv.End)
+	}
+	return points
+}
+
+// FromTogglepoints rebuilds a set from the flip positions returned by Togglepoints,
+// pairing them as [p0,p1), [p2,p3), ….
+//
+// Returns an error if the number of points is odd or they are not strictly increasing.
+//
+// For example:
+//
+//	[0, 2, 5, 7] → {[0,2), [5,7)}
+func FromTogglepoints(points []int) (IntervalSet, error) {
+	if len(points)%2 != 0 {
+		return nil, fmt.Errorf("odd number of togglepoints: %d", len(points))
+	}
+	for i := 1; i < len(points); i++ {
+		if points[i] <= points[i-1] {
+			return nil, fmt.Errorf("togglepoints not strictly increasing at index %d", i)
+		}
+	}
+	var set IntervalSet
+	for i := 0; i < len(points); i += 2 {
+		set = append(set, IntegerInterval{Start: points[i], End: points[i+1]})
+	}
+	return set, nil
+}
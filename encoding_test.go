@@ -0,0 +1,205 @@
+package interval
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"math/bits"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestIntervalSet_Key(t *testing.T) {
+	same := []IntervalSet{
+		{{0, 4}, {6, 8}},
+		{{6, 8}, {2, 4}, {0, 2}},
+		{{0, 3}, {6, 8}, {1, 4}, {5, 5}},
+	}
+	for _, set := range same[1:] {
+		if set.Key() != same[0].Key() {
+			t.Errorf("%v.Key() = %q, want %q", set, set.Key(), same[0].Key())
+		}
+	}
+	if (IntervalSet{{0, 4}, {6, 9}}).Key() == same[0].Key() {
+		t.Error("distinct sets share a key")
+	}
+	if IntervalSet(nil).Key() != (IntervalSet{{3, 3}}).Key() {
+		t.Error("empty sets do not share a key")
+	}
+}
+
+func TestIntervalSet_Gob(t *testing.T) {
+	set := make(IntervalSet, 1000)
+	for i := range set {
+		set[i] = IntegerInterval{Start: i * 7, End: i*7 + 3 + i%5}
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(set); err != nil {
+		t.Fatal(err)
+	}
+	size := buf.Len()
+	var decoded IntervalSet
+	if err := gob.NewDecoder(&buf).Decode(&decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(decoded, set) {
+		t.Fatalf("round trip mismatch: got %d intervals", len(decoded))
+	}
+
+	// IntervalSet のメソッドを持たない素の []IntegerInterval ならデフォルトの構造体エンコードになる
+	var plain bytes.Buffer
+	if err := gob.NewEncoder(&plain).Encode([]IntegerInterval(set)); err != nil {
+		t.Fatal(err)
+	}
+	if size >= plain.Len() {
+		t.Errorf("GobEncode size %d, default struct encoding %d", size, plain.Len())
+	}
+	t.Logf("GobEncode %d bytes, default %d bytes", size, plain.Len())
+}
+
+func TestIntervalSet_MarshalBinary(t *testing.T) {
+	r := rand.New(rand.NewPCG(15, 16))
+	for range 200 {
+		set := randomSet(r)
+		for i := range set {
+			set[i].Start -= 20 // 負の座標も含める
+			set[i].End -= 20
+		}
+		data, err := set.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var decoded IntervalSet
+		if err := decoded.UnmarshalBinary(data); err != nil {
+			t.Fatalf("%v: %v", set, err)
+		}
+		if !slices.Equal(decoded, set.canonical()) {
+			t.Fatalf("round trip of %v = %v", set, decoded)
+		}
+	}
+
+	// 実データに近い、ソート済みで近接した区間
+	set := make(IntervalSet, 1000)
+	for i := range set {
+		set[i] = IntegerInterval{Start: 100000 + i*40, End: 100000 + i*40 + 5 + i%20}
+	}
+	data, _ := set.MarshalBinary()
+	asJSON, _ := json.Marshal(set)
+	if len(data)*5 > len(asJSON) {
+		t.Errorf("binary %d bytes, JSON %d bytes", len(data), len(asJSON))
+	}
+	t.Logf("binary %d bytes, JSON %d bytes", len(data), len(asJSON))
+
+	var decoded IntervalSet
+	if err := decoded.UnmarshalBinary(data[:len(data)-1]); err == nil {
+		t.Error("expected error for truncated data")
+	}
+}
+
+func TestIntervalSet_Hash64(t *testing.T) {
+	a := IntervalSet{{0, 4}, {6, 8}}
+	b := IntervalSet{{6, 8}, {2, 4}, {0, 2}, {5, 5}}
+	if a.Hash64() != b.Hash64() {
+		t.Errorf("Hash64 differs for equal sets %v and %v", a, b)
+	}
+	distinct := []IntervalSet{nil, {{0, 4}}, {{0, 4}, {6, 9}}, {{1, 4}, {6, 8}}, {{0, 8}}}
+	for _, d := range distinct {
+		if d.Hash64() == a.Hash64() {
+			t.Errorf("Hash64 collides for %v and %v", d, a)
+		}
+	}
+}
+
+func TestIntervalSet_Signature(t *testing.T) {
+	base := IntegerInterval{0, 16}
+	set := IntervalSet{{0, 6}, {12, 16}}
+	if got := set.Signature(base, 4); got != 0b1011 {
+		t.Errorf("Signature = %b, want 1011", got)
+	}
+	if set.Signature(base, 16) != (IntervalSet{{12, 16}, {0, 3}, {3, 6}}).Signature(base, 16) {
+		t.Error("equal sets have different signatures")
+	}
+
+	// 1 セル分ずらすと、それぞれの区間の両端で 1 ビットずつ変わる
+	base = IntegerInterval{0, 64}
+	a := IntervalSet{{8, 20}, {40, 48}}
+	b := IntervalSet{{9, 21}, {41, 49}}
+	if d := bits.OnesCount64(a.Signature(base, 64) ^ b.Signature(base, 64)); d != 4 {
+		t.Errorf("Hamming distance after a one-cell shift = %d, want 4", d)
+	}
+	if got := set.Signature(base, 65); got != 0 {
+		t.Errorf("Signature with 65 bits = %b, want 0", got)
+	}
+}
+
+func TestParseRangeList(t *testing.T) {
+	tests := []struct {
+		s    string
+		want IntervalSet
+	}{
+		{"0-3,5,7-9", IntervalSet{{0, 4}, {5, 6}, {7, 10}}},
+		{" 7 - 9 , 0-3, 4 ", IntervalSet{{0, 5}, {7, 10}}},
+		{"-5--3,-1", IntervalSet{{-5, -2}, {-1, 0}}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		got, err := ParseRangeList(tt.s)
+		if err != nil || !slices.Equal(got, tt.want) {
+			t.Errorf("ParseRangeList(%q) = %v, %v, want %v", tt.s, got, err, tt.want)
+		}
+		if err == nil {
+			if back, _ := ParseRangeList(got.ToRangeList()); !slices.Equal(back, got) {
+				t.Errorf("round trip of %v via %q = %v", got, got.ToRangeList(), back)
+			}
+		}
+	}
+	if got := (IntervalSet{{0, 4}, {5, 6}, {7, 10}}).ToRangeList(); got != "0-3,5,7-9" {
+		t.Errorf("ToRangeList() = %q", got)
+	}
+	for _, bad := range []string{"5-3", "1,x", "1,,2", "1-2-3"} {
+		if _, err := ParseRangeList(bad); err == nil {
+			t.Errorf("ParseRangeList(%q) = nil error", bad)
+		}
+	}
+	if _, err := ParseRangeList("0-3,5-3"); err == nil || !strings.Contains(err.Error(), `"5-3"`) {
+		t.Errorf("error %v does not name the offending token", err)
+	}
+}
+
+func TestIntervalSet_Togglepoints(t *testing.T) {
+	tests := []struct {
+		set  IntervalSet
+		want []int
+	}{
+		{IntervalSet{{5, 7}, {0, 2}}, []int{0, 2, 5, 7}},
+		{IntervalSet{{0, 2}, {2, 4}, {3, 6}}, []int{0, 6}},
+		{IntervalSet{{3, 3}}, nil},
+		{nil, nil},
+	}
+	for _, tt := range tests {
+		if got := tt.set.Togglepoints(); !slices.Equal(got, tt.want) {
+			t.Errorf("%v.Togglepoints() = %v, want %v", tt.set, got, tt.want)
+		}
+	}
+}
+
+func TestFromTogglepoints(t *testing.T) {
+	got, err := FromTogglepoints([]int{0, 2, 5, 7})
+	if want := (IntervalSet{{0, 2}, {5, 7}}); err != nil || !slices.Equal(got, want) {
+		t.Errorf("FromTogglepoints = %v, %v, want %v", got, err, want)
+	}
+	for _, set := range []IntervalSet{{{5, 9}, {0, 3}, {3, 4}}, {{-4, -1}}, nil} {
+		back, err := FromTogglepoints(set.Togglepoints())
+		if err != nil || !slices.Equal(back, set.canonical()) {
+			t.Errorf("round trip of %v = %v, %v", set, back, err)
+		}
+	}
+	for _, bad := range [][]int{{0, 2, 5}, {0, 2, 2, 4}, {3, 1}} {
+		if _, err := FromTogglepoints(bad); err == nil {
+			t.Errorf("FromTogglepoints(%v) = nil error", bad)
+		}
+	}
+}
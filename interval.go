@@ -1,6 +1,7 @@
 package interval
 
 import (
+	"cmp"
 	"errors"
 	"fmt"
 	"slices"
@@ -60,6 +61,9 @@ func (set IntervalSet) ExtractSlices(text string) ([]string, error) {
 // "abc" 全体 → [0,3)
 // "a" → [0,1)、補集合 → "bc" = [1,3)
 // "c" → [2,3)、補集合 → "ab" = [0,2)
+//
+// 負の座標も有効な座標として扱う（例: [-5,-2)）。代数的な操作はすべて ℤ 上で定義される。
+// 文字列を扱う操作（Slice, Replace, Insert など）だけが 0 ≤ Start, End ≤ len(text) を要求する。
 type IntegerInterval struct {
 	Start int
 	End   int
@@ -97,6 +101,25 @@ func (iv IntegerInterval) Intersect(other IntegerInterval) (IntegerInterval, boo
 	return IntegerInterval{}, false
 }
 
+// IntersectIntervals returns the region common to all of ivs: [max Start, min End).
+// Returns false if it is empty, including when no intervals are given.
+//
+// IntersectIntervals(iv₁, …, ivₙ) = iv₁ ∩ … ∩ ivₙ, if non-empty
+func IntersectIntervals(ivs ...IntegerInterval) (IntegerInterval, bool) {
+	if len(ivs) == 0 {
+		return IntegerInterval{}, false
+	}
+	common := ivs[0]
+	for _, iv := range ivs[1:] {
+		common.Start = max(common.Start, iv.Start)
+		common.End = min(common.End, iv.End)
+	}
+	if common.Start < common.End {
+		return common, true
+	}
+	return IntegerInterval{}, false
+}
+
 // 連続または重複していればマージ可能
 // → [0,2) + [2,5) → [0,5)
 // Merge(other) = [Start, End) ∪ [other.Start, other.End), if Overlaps or IsAdjacent
@@ -150,9 +173,9 @@ func (iv IntegerInterval) IsAdjacent(other IntegerInterval) bool {
 // Compare by Start, then End
 func (iv IntegerInterval) Compare(other IntegerInterval) int {
 	if iv.Start != other.Start {
-		return iv.Start - other.Start
+		return cmp.Compare(iv.Start, other.Start)
 	}
-	return iv.End - other.End
+	return cmp.Compare(iv.End, other.End)
 }
 
 // Covers(other) ⇔ [Start, End) ⊇ [other.Start, other.End)
@@ -176,26 +199,87 @@ func (set IntervalSet) Normalize() IntervalSet {
 	if len(set) == 0 {
 		return nil
 	}
+	return set.NormalizeInto(make(IntervalSet, 0, len(set)))
+}
 
+// NormalizeInto is like Normalize but writes the result into dst[:0], reusing its storage.
+//
+// If cap(dst) ≥ len(set), no allocation takes place. dst must not share storage with set.
+// The returned slice aliases dst, so it is only valid until dst is reused.
+//
+// For hot loops, buffers can be pooled with sync.Pool:
+//
+//	var pool = sync.Pool{New: func() any { return new(IntervalSet) }}
+//
+//	buf := pool.Get().(*IntervalSet)
+//	*buf = set.NormalizeInto(*buf)
+//	// ... use *buf ...
+//	pool.Put(buf)
+//
+// NormalizeInto(dst) = Normalize(set), stored in dst
+func (set IntervalSet) NormalizeInto(dst IntervalSet) IntervalSet {
 	// まずコピーしてソート
-	sorted := slices.Clone(set)
+	sorted := append(dst[:0], set...)
+	if len(sorted) == 0 {
+		return sorted
+	}
 	slices.SortFunc(sorted, func(a, b IntegerInterval) int {
 		return a.Compare(b)
 	})
 
-	result := make(IntervalSet, 0, len(sorted))
-	current := sorted[0]
-
+	// ソート済みなのでその場で詰めていける
+	n := 0
 	for _, next := range sorted[1:] {
-		if merged, ok := current.Merge(next); ok {
-			current = merged
+		if merged, ok := sorted[n].Merge(next); ok {
+			sorted[n] = merged
 		} else {
+			n++
+			sorted[n] = next
+		}
+	}
+	return sorted[:n+1]
+}
+
+// MergedInterval is an interval of a normalized set together with the indices
+// of the original members that were merged into it.
+type MergedInterval struct {
+	Merged  IntegerInterval
+	Sources []int
+}
+
+// NormalizeWithProvenance is like Normalize but also reports, for each merged interval,
+// the indices of the original members it was built from (in ascending order).
+//
+// For example:
+//
+//	set    = {[5,6), [0,2), [1,4)}
+//	result = {[0,4) ← 1,2}, {[5,6) ← 0}
+func (set IntervalSet) NormalizeWithProvenance() []MergedInterval {
+	if len(set) == 0 {
+		return nil
+	}
+	order := make([]int, len(set))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return set[a].Compare(set[b])
+	})
+
+	var result []MergedInterval
+	current := MergedInterval{Merged: set[order[0]], Sources: []int{order[0]}}
+	for _, i := range order[1:] {
+		if merged, ok := current.Merged.Merge(set[i]); ok {
+			current.Merged = merged
+			current.Sources = append(current.Sources, i)
+		} else {
+			slices.Sort(current.Sources)
 			result = append(result, current)
-			current = next
+			current = MergedInterval{Merged: set[i], Sources: []int{i}}
 		}
 	}
-	result = append(result, current)
-	return result
+	slices.Sort(current.Sources)
+	return append(result, current)
 }
 
 // ContainsPoint reports whether the given integer n is contained in any of the intervals in the set.
@@ -327,6 +411,45 @@ func (set IntervalSet) Complement(base IntegerInterval) IntervalSet {
 	return subtracted.Normalize()
 }
 
+// ComplementFast returns the same result as Complement in a single linear pass:
+// the set is normalized once and the gaps between merged intervals within base are emitted in order.
+//
+// This is O(n log n) for the normalization, instead of O(n²) for Complement's repeated subtraction.
+//
+// The one difference is an invalid base (Start > End): ComplementFast treats it as empty and
+// returns nil, while Complement returns {base} unchanged, so no invalid interval leaks out.
+//
+// For example:
+//
+//	set  = {[2,3), [0,1)}
+//	base = [0,3)
+//	result = {[1,2)}
+//
+// ComplementFast(base) = Complement(base), for a valid base
+func (set IntervalSet) ComplementFast(base IntegerInterval) IntervalSet {
+	if base.Length() <= 0 {
+		return nil
+	}
+	var result IntervalSet
+	cursor := base.Start
+	for _, iv := range set.canonical() {
+		if iv.End <= cursor {
+			continue
+		}
+		if iv.Start >= base.End {
+			break
+		}
+		if iv.Start > cursor {
+			result = append(result, IntegerInterval{Start: cursor, End: iv.Start})
+		}
+		cursor = iv.End
+	}
+	if cursor < base.End {
+		result = append(result, IntegerInterval{Start: cursor, End: base.End})
+	}
+	return result
+}
+
 func (iv IntegerInterval) String() string {
 	return fmt.Sprintf("[%d,%d)", iv.Start, iv.End)
 }
@@ -341,3 +464,22 @@ func (set IntervalSet) String() string {
 	}
 	return "{" + strings.Join(parts, ", ") + "}"
 }
+
+// MultilineString formats the set one interval per line, each line ending in a newline,
+// sorted by Compare but not merged, for golden files that diff cleanly.
+//
+// Returns "" for an empty set.
+//
+// For example:
+//
+//	{[5,6), [0,2), [0,1)} → "[0,1)\n[0,2)\n[5,6)\n"
+func (set IntervalSet) MultilineString() string {
+	sorted := slices.Clone(set)
+	slices.SortFunc(sorted, IntegerInterval.Compare)
+	var b strings.Builder
+	for _, iv := range sorted {
+		b.WriteString(iv.String())
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
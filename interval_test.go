@@ -2,6 +2,9 @@ package interval
 
 import (
 	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
 	"testing"
 )
 
@@ -11,3 +14,195 @@ func TestIntervalSet_Union(t *testing.T) {
 	union := set.Union(other)
 	fmt.Println(union.String())
 }
+
+// randomSet はテスト用に [0, 50) 内のランダムな区間集合を返す（重複・隣接を含みうる）。
+func randomSet(r *rand.Rand) IntervalSet {
+	n := r.IntN(6)
+	set := make(IntervalSet, 0, n)
+	for range n {
+		start := r.IntN(45)
+		set = append(set, IntegerInterval{Start: start, End: start + r.IntN(6)})
+	}
+	return set
+}
+
+func TestIntervalSet_NormalizeInto(t *testing.T) {
+	r := rand.New(rand.NewPCG(3, 4))
+	buf := make(IntervalSet, 0, 8)
+	for range 200 {
+		set := randomSet(r)
+		original := slices.Clone(set)
+		buf = set.NormalizeInto(buf)
+		if want := set.Normalize(); !slices.Equal(buf, want) {
+			t.Fatalf("%v.NormalizeInto = %v, want %v", set, buf, want)
+		}
+		if !slices.Equal(set, original) {
+			t.Fatalf("NormalizeInto modified its receiver: %v → %v", original, set)
+		}
+	}
+}
+
+func benchmarkSet() IntervalSet {
+	return IntervalSet{{8, 10}, {0, 2}, {1, 4}, {12, 13}, {5, 6}, {9, 11}, {3, 5}, {20, 22}}
+}
+
+var benchSink IntervalSet
+
+func BenchmarkIntervalSet_Normalize(b *testing.B) {
+	set := benchmarkSet()
+	b.ReportAllocs()
+	for range b.N {
+		benchSink = set.Normalize()
+	}
+}
+
+func BenchmarkIntervalSet_NormalizeInto(b *testing.B) {
+	set := benchmarkSet()
+	buf := make(IntervalSet, 0, len(set))
+	b.ReportAllocs()
+	for range b.N {
+		buf = set.NormalizeInto(buf)
+	}
+	benchSink = buf
+}
+
+func TestNegativeCoordinates(t *testing.T) {
+	a := IntegerInterval{-5, -2}
+	b := IntegerInterval{-1, 3}
+	c := IntegerInterval{-3, 1}
+
+	if _, ok := a.Merge(b); ok {
+		t.Errorf("%v.Merge(%v) succeeded for separated intervals", a, b)
+	}
+	if got, ok := a.Merge(c); !ok || got != (IntegerInterval{-5, 1}) {
+		t.Errorf("%v.Merge(%v) = %v, %v", a, c, got, ok)
+	}
+	if got, ok := (IntegerInterval{-5, -1}).Merge(b); !ok || got != (IntegerInterval{-5, 3}) {
+		t.Errorf("adjacent merge = %v, %v", got, ok)
+	}
+	if got, want := b.Subtract(IntegerInterval{-3, 0}), []IntegerInterval{{0, 3}}; !slices.Equal(got, want) {
+		t.Errorf("Subtract = %v, want %v", got, want)
+	}
+	if got, want := (IntegerInterval{-10, 10}).Subtract(a), []IntegerInterval{{-10, -5}, {-2, 10}}; !slices.Equal(got, want) {
+		t.Errorf("Subtract = %v, want %v", got, want)
+	}
+
+	set := IntervalSet{b, a, c, {-20, -10}}
+	if got, want := set.Normalize(), (IntervalSet{{-20, -10}, {-5, 3}}); !slices.Equal(got, want) {
+		t.Errorf("Normalize() = %v, want %v", got, want)
+	}
+	if got, want := (IntervalSet{a, b}).Intersect(IntervalSet{c}), (IntervalSet{{-3, -2}, {-1, 1}}); !slices.Equal(got, want) {
+		t.Errorf("Intersect = %v, want %v", got, want)
+	}
+	if got, want := (IntervalSet{a, b}).Complement(IntegerInterval{-6, 0}), (IntervalSet{{-6, -5}, {-2, -1}}); !slices.Equal(got, want) {
+		t.Errorf("Complement = %v, want %v", got, want)
+	}
+}
+
+func TestIntervalSet_NormalizeWithProvenance(t *testing.T) {
+	set := IntervalSet{{5, 6}, {2, 4}, {0, 2}, {10, 12}, {1, 3}}
+	got := set.NormalizeWithProvenance()
+	want := []MergedInterval{
+		{IntegerInterval{0, 4}, []int{1, 2, 4}},
+		{IntegerInterval{5, 6}, []int{0}},
+		{IntegerInterval{10, 12}, []int{3}},
+	}
+	if !slices.EqualFunc(got, want, func(a, b MergedInterval) bool {
+		return a.Merged == b.Merged && slices.Equal(a.Sources, b.Sources)
+	}) {
+		t.Errorf("NormalizeWithProvenance() = %v, want %v", got, want)
+	}
+	for i, m := range got {
+		if m.Merged != set.Normalize()[i] {
+			t.Errorf("merged interval %d = %v, differs from Normalize", i, m.Merged)
+		}
+	}
+}
+
+func TestIntervalSet_ComplementFast(t *testing.T) {
+	r := rand.New(rand.NewPCG(23, 24))
+	for range 500 {
+		set := randomSet(r)
+		start := r.IntN(50) - 5
+		base := IntegerInterval{start, start + r.IntN(30)}
+		if got, want := set.ComplementFast(base), set.Complement(base); !slices.Equal(got, want) {
+			t.Fatalf("%v.ComplementFast(%v) = %v, want %v", set, base, got, want)
+		}
+	}
+	// 逆向きの base は空として扱い、Complement と違って {base} を返さない
+	reversed := IntegerInterval{5, 2}
+	for _, set := range []IntervalSet{nil, {{0, 1}}, {{3, 4}}} {
+		if got := set.ComplementFast(reversed); got != nil {
+			t.Errorf("%v.ComplementFast(%v) = %v, want nil", set, reversed, got)
+		}
+		if got := set.Complement(reversed); !slices.Equal(got, IntervalSet{reversed}) {
+			t.Errorf("%v.Complement(%v) = %v, want {%v}", set, reversed, got, reversed)
+		}
+	}
+}
+
+func benchmarkLargeSet() IntervalSet {
+	r := rand.New(rand.NewPCG(25, 26))
+	set := make(IntervalSet, 2000)
+	for i := range set {
+		start := r.IntN(100000)
+		set[i] = IntegerInterval{start, start + r.IntN(40)}
+	}
+	return set
+}
+
+func BenchmarkIntervalSet_Complement(b *testing.B) {
+	set := benchmarkLargeSet()
+	base := IntegerInterval{0, 100000}
+	b.ReportAllocs()
+	for range b.N {
+		benchSink = set.Complement(base)
+	}
+}
+
+func BenchmarkIntervalSet_ComplementFast(b *testing.B) {
+	set := benchmarkLargeSet()
+	base := IntegerInterval{0, 100000}
+	b.ReportAllocs()
+	for range b.N {
+		benchSink = set.ComplementFast(base)
+	}
+}
+
+func TestIntervalSet_MultilineString(t *testing.T) {
+	set := IntervalSet{{5, 6}, {0, 2}, {0, 1}, {5, 6}}
+	want := "[0,1)\n[0,2)\n[5,6)\n[5,6)\n"
+	if got := set.MultilineString(); got != want {
+		t.Errorf("MultilineString() = %q, want %q", got, want)
+	}
+	if got := (IntervalSet{{5, 6}, {0, 1}, {5, 6}, {0, 2}}).MultilineString(); got != want {
+		t.Errorf("MultilineString() depends on input order: %q", got)
+	}
+	if got := strings.Count(set.MultilineString(), "\n"); got != len(set) {
+		t.Errorf("got %d lines, want %d", got, len(set))
+	}
+	if got := IntervalSet(nil).MultilineString(); got != "" {
+		t.Errorf("MultilineString() of empty set = %q", got)
+	}
+}
+
+func TestIntersectIntervals(t *testing.T) {
+	tests := []struct {
+		ivs  []IntegerInterval
+		want IntegerInterval
+		ok   bool
+	}{
+		{[]IntegerInterval{{0, 10}, {3, 8}, {5, 12}}, IntegerInterval{5, 8}, true},
+		// [0,4) と [3,8) は重なるが 3 つ全部の共通部分はない
+		{[]IntegerInterval{{0, 4}, {3, 8}, {6, 9}}, IntegerInterval{}, false},
+		{[]IntegerInterval{{0, 4}, {4, 8}}, IntegerInterval{}, false},
+		{[]IntegerInterval{{2, 5}}, IntegerInterval{2, 5}, true},
+		{[]IntegerInterval{{2, 2}}, IntegerInterval{}, false},
+		{nil, IntegerInterval{}, false},
+	}
+	for _, tt := range tests {
+		if got, ok := IntersectIntervals(tt.ivs...); got != tt.want || ok != tt.ok {
+			t.Errorf("IntersectIntervals(%v) = %v, %v, want %v, %v", tt.ivs, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package interval
+
+import (
+	"fmt"
+	"slices"
+)
+
+// LabeledInterval is an interval carrying a label of type T (a token kind, a weight, a tag, ...).
+type LabeledInterval[T any] struct {
+	IntegerInterval
+	Label T
+}
+
+// String formats the interval followed by its label, e.g. "[0,3) text".
+func (l LabeledInterval[T]) String() string {
+	return fmt.Sprintf("%v %v", l.IntegerInterval, l.Label)
+}
+
+// LabeledSet is a set of labeled intervals. Like IntervalSet it may be unsorted and overlapping.
+type LabeledSet[T any] []LabeledInterval[T]
+
+// Intervals returns the intervals of the set without their labels, in the same order.
+func (ls LabeledSet[T]) Intervals() IntervalSet {
+	result := make(IntervalSet, len(ls))
+	for i, l := range ls {
+		result[i] = l.IntegerInterval
+	}
+	return result
+}
+
+// Subtract removes iv from every member, keeping each member's label on the zero, one or two
+// pieces that remain, in the original order.
+//
+// For example:
+//
+//	ls = {[0,10) "a", [5,8) "b"}
+//	iv = [3,7)
+//	result = {[0,3) "a", [7,10) "a", [7,8) "b"}
+//
+// Subtract(iv) = { (piece, label) | (s, label) ∈ ls, piece ∈ s − iv }
+func (ls LabeledSet[T]) Subtract(iv IntegerInterval) LabeledSet[T] {
+	var result LabeledSet[T]
+	for _, l := range ls {
+		for _, piece := range l.IntegerInterval.Subtract(iv) {
+			result = append(result, LabeledInterval[T]{IntegerInterval: piece, Label: l.Label})
+		}
+	}
+	return result
+}
+
+// OverlayOver paints a on top of b: members of a are kept whole, and members of b
+// are clipped around a's coverage, keeping their labels on every remaining piece.
+// The result is sorted by position.
+//
+// For example:
+//
+//	a = {[3,5) "kw"}
+//	b = {[0,10) "text"}
+//	result = {[0,3) "text", [3,5) "kw", [5,10) "text"}
+//
+// OverlayOver(b) = a ∪ { (s − ⋃a, label) | (s, label) ∈ b }
+func (a LabeledSet[T]) OverlayOver(b LabeledSet[T]) LabeledSet[T] {
+	covered := a.Intervals().Normalize()
+	result := slices.Clone(a)
+	for _, l := range b {
+		for _, piece := range covered.ComplementIn(IntervalSet{l.IntegerInterval}) {
+			result = append(result, LabeledInterval[T]{IntegerInterval: piece, Label: l.Label})
+		}
+	}
+	slices.SortStableFunc(result, func(x, y LabeledInterval[T]) int {
+		return x.Compare(y.IntegerInterval)
+	})
+	return result
+}
+
+// NormalizeMerge merges overlapping and adjacent members like Normalize, combining the labels
+// of each merged group with combine, sorted by position.
+//
+// Within a group, labels are folded left to right in sorted order (stable for equal intervals):
+// combine(combine(l₁, l₂), l₃), …. A member that merges with nothing keeps its label unchanged.
+//
+// For example:
+//
+//	ls      = {[0,3) 1, [2,5) 2, [5,6) 4, [8,9) 8}
+//	combine = +
+//	result  = {[0,6) 7, [8,9) 8}
+func (ls LabeledSet[T]) NormalizeMerge(combine func(a, b T) T) LabeledSet[T] {
+	if len(ls) == 0 {
+		return nil
+	}
+	sorted := slices.Clone(ls)
+	slices.SortStableFunc(sorted, func(x, y LabeledInterval[T]) int {
+		return x.Compare(y.IntegerInterval)
+	})
+	n := 0
+	for _, next := range sorted[1:] {
+		if merged, ok := sorted[n].Merge(next.IntegerInterval); ok {
+			sorted[n] = LabeledInterval[T]{IntegerInterval: merged, Label: combine(sorted[n].Label, next.Label)}
+		} else {
+			n++
+			sorted[n] = next
+		}
+	}
+	return sorted[:n+1]
+}
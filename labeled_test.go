@@ -0,0 +1,61 @@
+package interval
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLabeledSet_OverlayOver(t *testing.T) {
+	a := LabeledSet[string]{{IntegerInterval{3, 5}, "kw"}, {IntegerInterval{12, 14}, "num"}}
+	b := LabeledSet[string]{{IntegerInterval{0, 10}, "text"}, {IntegerInterval{13, 16}, "text"}}
+	want := LabeledSet[string]{
+		{IntegerInterval{0, 3}, "text"},
+		{IntegerInterval{3, 5}, "kw"},
+		{IntegerInterval{5, 10}, "text"},
+		{IntegerInterval{12, 14}, "num"},
+		{IntegerInterval{14, 16}, "text"},
+	}
+	if got := a.OverlayOver(b); !slices.Equal(got, want) {
+		t.Errorf("OverlayOver = %v, want %v", got, want)
+	}
+}
+
+func TestLabeledSet_NormalizeMerge(t *testing.T) {
+	ls := LabeledSet[int]{
+		{IntegerInterval{8, 9}, 8},
+		{IntegerInterval{2, 5}, 2},
+		{IntegerInterval{0, 3}, 1},
+		{IntegerInterval{5, 6}, 4},
+	}
+	want := LabeledSet[int]{{IntegerInterval{0, 6}, 7}, {IntegerInterval{8, 9}, 8}}
+	if got := ls.NormalizeMerge(func(a, b int) int { return a + b }); !slices.Equal(got, want) {
+		t.Errorf("NormalizeMerge(+) = %v, want %v", got, want)
+	}
+
+	tags := LabeledSet[string]{{IntegerInterval{3, 6}, "b"}, {IntegerInterval{0, 3}, "a"}}
+	wantTags := LabeledSet[string]{{IntegerInterval{0, 6}, "a,b"}}
+	if got := tags.NormalizeMerge(func(a, b string) string { return a + "," + b }); !slices.Equal(got, wantTags) {
+		t.Errorf("NormalizeMerge(join) = %v, want %v", got, wantTags)
+	}
+	if got := LabeledSet[int](nil).NormalizeMerge(func(a, b int) int { return a + b }); got != nil {
+		t.Errorf("NormalizeMerge of empty set = %v", got)
+	}
+}
+
+func TestLabeledSet_Subtract(t *testing.T) {
+	ls := LabeledSet[string]{
+		{IntegerInterval{0, 10}, "a"},
+		{IntegerInterval{5, 8}, "b"},
+		{IntegerInterval{4, 6}, "c"},
+		{IntegerInterval{12, 14}, "d"},
+	}
+	want := LabeledSet[string]{
+		{IntegerInterval{0, 3}, "a"},
+		{IntegerInterval{7, 10}, "a"},
+		{IntegerInterval{7, 8}, "b"},
+		{IntegerInterval{12, 14}, "d"},
+	}
+	if got := ls.Subtract(IntegerInterval{3, 7}); !slices.Equal(got, want) {
+		t.Errorf("Subtract([3,7)) = %v, want %v", got, want)
+	}
+}
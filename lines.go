@@ -0,0 +1,140 @@
+package interval
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// LineColumn converts the interval into 0-based (line, column) coordinates within text.
+//
+// Lines are separated by '\n' and columns are byte offsets within the line.
+// An endpoint exactly at a '\n' belongs to the line that newline terminates;
+// an endpoint right after it is column 0 of the next line.
+// Returns an error if the interval is out of range.
+//
+// For example:
+//
+//	text = "ab\ncd"
+//	iv   = [1,4) → (0,1)–(1,1)
+//	iv   = [0,2) → (0,0)–(0,2)
+func (iv IntegerInterval) LineColumn(text string) (startLine, startCol, endLine, endCol int, err error) {
+	if !iv.IsValid() || iv.Start < 0 || iv.End > len(text) {
+		return 0, 0, 0, 0, errors.New("out of range")
+	}
+	startLine, startCol = lineColumnAt(text, iv.Start)
+	endLine, endCol = lineColumnAt(text, iv.End)
+	return startLine, startCol, endLine, endCol, nil
+}
+
+// lineColumnAt は text 中のバイト位置 pos を (行, 列) に変換する。
+func lineColumnAt(text string, pos int) (line, col int) {
+	before := text[:pos]
+	line = strings.Count(before, "\n")
+	col = pos - (strings.LastIndexByte(before, '\n') + 1)
+	return line, col
+}
+
+// IntervalFromLineColumn converts 0-based (line, column) coordinates within text into a byte interval.
+// It is the inverse of LineColumn.
+//
+// A column may point at most just past the last byte of its line (i.e. at its '\n').
+// Returns an error if any coordinate is out of range or the result would be invalid.
+//
+// For example:
+//
+//	text = "ab\ncd"
+//	(0,1)–(1,1) → [1,4)
+func IntervalFromLineColumn(text string, startLine, startCol, endLine, endCol int) (IntegerInterval, error) {
+	start, err := offsetAt(text, startLine, startCol)
+	if err != nil {
+		return IntegerInterval{}, err
+	}
+	end, err := offsetAt(text, endLine, endCol)
+	if err != nil {
+		return IntegerInterval{}, err
+	}
+	iv := IntegerInterval{Start: start, End: end}
+	if !iv.IsValid() {
+		return IntegerInterval{}, fmt.Errorf("invalid interval %v", iv)
+	}
+	return iv, nil
+}
+
+// offsetAt は (行, 列) を text 中のバイト位置に変換する。
+func offsetAt(text string, line, col int) (int, error) {
+	if line < 0 || col < 0 {
+		return 0, fmt.Errorf("line %d column %d out of range", line, col)
+	}
+	lineStart := 0
+	for range line {
+		i := strings.IndexByte(text[lineStart:], '\n')
+		if i < 0 {
+			return 0, fmt.Errorf("line %d out of range", line)
+		}
+		lineStart += i + 1
+	}
+	lineLength := strings.IndexByte(text[lineStart:], '\n')
+	if lineLength < 0 {
+		lineLength = len(text) - lineStart
+	}
+	if col > lineLength {
+		return 0, fmt.Errorf("line %d column %d out of range", line, col)
+	}
+	return lineStart + col, nil
+}
+
+// LineIntervals returns the byte interval of each line of text, in order.
+//
+// Each line's interval includes its trailing '\n', so the intervals tile [0, len(text))
+// and concatenating their slices gives back text. A trailing '\n' ends the last line and
+// does not start an empty one; text without any '\n' is a single line.
+// Returns nil for empty text.
+//
+// For example:
+//
+//	text = "ab\ncd\n"
+//	result = {[0,3), [3,6)}
+func LineIntervals(text string) IntervalSet {
+	var result IntervalSet
+	for start := 0; start < len(text); {
+		end := len(text)
+		if i := strings.IndexByte(text[start:], '\n'); i >= 0 {
+			end = start + i + 1
+		}
+		result = append(result, IntegerInterval{Start: start, End: end})
+		start = end
+	}
+	return result
+}
+
+// TouchedLines returns the 0-based numbers of the lines of text (see LineIntervals) that the
+// interval overlaps, in increasing order.
+//
+// A line's trailing '\n' belongs to it, so an interval ending right after a newline does not
+// touch the next line. An empty interval touches no lines.
+// Returns an error if the interval is out of range.
+//
+// For example:
+//
+//	text = "ab\ncd\nef"
+//	iv   = [2,4) → [0, 1]
+//	iv   = [3,6) → [1]
+func (iv IntegerInterval) TouchedLines(text string) ([]int, error) {
+	if !iv.IsValid() || iv.Start < 0 || iv.End > len(text) {
+		return nil, errors.New("out of range")
+	}
+	if iv.IsEmpty() {
+		return nil, nil
+	}
+	var result []int
+	for n, line := range LineIntervals(text) {
+		if line.Start >= iv.End {
+			break
+		}
+		if line.Overlaps(iv) {
+			result = append(result, n)
+		}
+	}
+	return result, nil
+}
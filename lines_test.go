@@ -0,0 +1,101 @@
+package interval
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestIntegerInterval_LineColumn(t *testing.T) {
+	text := "ab\ncde\n\nf"
+	tests := []struct {
+		iv   IntegerInterval
+		want [4]int
+	}{
+		{IntegerInterval{0, 2}, [4]int{0, 0, 0, 2}}, // 改行の直前で終わる
+		{IntegerInterval{1, 5}, [4]int{0, 1, 1, 2}},
+		{IntegerInterval{3, 7}, [4]int{1, 0, 2, 0}}, // 改行の直後で終わる
+		{IntegerInterval{6, 9}, [4]int{1, 3, 3, 1}},
+		{IntegerInterval{7, 7}, [4]int{2, 0, 2, 0}},
+	}
+	for _, tt := range tests {
+		sl, sc, el, ec, err := tt.iv.LineColumn(text)
+		if got := [4]int{sl, sc, el, ec}; err != nil || got != tt.want {
+			t.Errorf("%v.LineColumn = %v, %v, want %v", tt.iv, got, err, tt.want)
+		}
+	}
+	if _, _, _, _, err := (IntegerInterval{5, 10}).LineColumn(text); err == nil {
+		t.Error("expected out of range error")
+	}
+}
+
+func TestIntervalFromLineColumn(t *testing.T) {
+	text := "ab\ncde\n\nf"
+	for start := 0; start <= len(text); start++ {
+		for end := start; end <= len(text); end++ {
+			iv := IntegerInterval{start, end}
+			sl, sc, el, ec, _ := iv.LineColumn(text)
+			if got, err := IntervalFromLineColumn(text, sl, sc, el, ec); err != nil || got != iv {
+				t.Errorf("IntervalFromLineColumn(%d,%d,%d,%d) = %v, %v, want %v", sl, sc, el, ec, got, err, iv)
+			}
+		}
+	}
+	bad := [][4]int{{0, 3, 1, 0}, {0, 0, 4, 0}, {1, 0, 0, 0}, {-1, 0, 0, 0}}
+	for _, c := range bad {
+		if _, err := IntervalFromLineColumn(text, c[0], c[1], c[2], c[3]); err == nil {
+			t.Errorf("IntervalFromLineColumn(%v) = nil error", c)
+		}
+	}
+}
+
+func TestLineIntervals(t *testing.T) {
+	tests := []struct {
+		text string
+		want IntervalSet
+	}{
+		{"ab\ncd\n", IntervalSet{{0, 3}, {3, 6}}},
+		{"ab\ncd", IntervalSet{{0, 3}, {3, 5}}},
+		{"\n\nx", IntervalSet{{0, 1}, {1, 2}, {2, 3}}},
+		{"no newline", IntervalSet{{0, 10}}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		got := LineIntervals(tt.text)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("LineIntervals(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+		var joined strings.Builder
+		for _, iv := range got {
+			joined.WriteString(tt.text[iv.Start:iv.End])
+		}
+		if joined.String() != tt.text {
+			t.Errorf("lines of %q concatenate to %q", tt.text, joined.String())
+		}
+	}
+}
+
+func TestIntegerInterval_TouchedLines(t *testing.T) {
+	text := "ab\ncd\nef" // 行0 [0,3) 行1 [3,6) 行2 [6,8)
+	tests := []struct {
+		iv   IntegerInterval
+		want []int
+	}{
+		{IntegerInterval{0, 2}, []int{0}},
+		{IntegerInterval{4, 7}, []int{1, 2}},
+		{IntegerInterval{1, 8}, []int{0, 1, 2}},
+		{IntegerInterval{3, 6}, []int{1}}, // 行末の境界で終わる
+		{IntegerInterval{2, 3}, []int{0}}, // 改行だけ
+		{IntegerInterval{4, 4}, nil},
+	}
+	for _, tt := range tests {
+		got, err := tt.iv.TouchedLines(text)
+		if err != nil || !slices.Equal(got, tt.want) {
+			t.Errorf("%v.TouchedLines = %v, %v, want %v", tt.iv, got, err, tt.want)
+		}
+	}
+	for _, iv := range []IntegerInterval{{-1, 2}, {5, 9}, {4, 3}} {
+		if _, err := iv.TouchedLines(text); err == nil {
+			t.Errorf("%v.TouchedLines: expected out of range error", iv)
+		}
+	}
+}
@@ -0,0 +1,356 @@
+package interval
+
+import (
+	"cmp"
+	"slices"
+	"sort"
+)
+
+// TotalLength returns the number of integers covered by the set.
+//
+// Overlapping intervals are counted once.
+//
+// For example:
+//
+//	set    = {[0,3), [2,5), [7,8)}
+//	result = 6
+//
+// TotalLength() = |⋃(iv ∈ set)|
+func (set IntervalSet) TotalLength() int {
+	total := 0
+	for _, iv := range set.Normalize() {
+		total += iv.Length()
+	}
+	return total
+}
+
+// DepthIntegral returns the coverage depth summed over all positions.
+//
+// Unlike TotalLength, overlaps count once per member covering them, so this equals the plain
+// sum of the members' lengths (empty and invalid members count 0).
+//
+// For example:
+//
+//	set    = {[0,3), [2,5), [7,8)}
+//	result = 7
+//
+// DepthIntegral() = Σ(x) #{iv ∈ set | x ∈ iv} = Σ(iv ∈ set) |iv|
+func (set IntervalSet) DepthIntegral() int {
+	total := 0
+	for _, iv := range set {
+		total += max(iv.Length(), 0)
+	}
+	return total
+}
+
+// UnionLength returns the number of integers covered by the set or other,
+// without building the union.
+//
+// For example:
+//
+//	a = {[0,3)}
+//	b = {[2,5)}
+//	result = 5
+//
+// UnionLength(set') = |set ∪ set'|
+func (set IntervalSet) UnionLength(other IntervalSet) int {
+	return sweepLength(set, other, func(inA, inB bool) bool {
+		return inA || inB
+	})
+}
+
+// IntersectLength returns the number of integers covered by both the set and other,
+// without building the intersection.
+//
+// For example:
+//
+//	a = {[0,3)}
+//	b = {[2,5)}
+//	result = 1
+//
+// IntersectLength(set') = |set ∩ set'|
+func (set IntervalSet) IntersectLength(other IntervalSet) int {
+	return sweepLength(set, other, func(inA, inB bool) bool {
+		return inA && inB
+	})
+}
+
+// SymmetricDifferenceLength returns the number of integers covered by exactly one of
+// the set and other, without building the symmetric difference.
+//
+// For example:
+//
+//	a = {[0,3)}
+//	b = {[2,5)}
+//	result = 4
+//
+// SymmetricDifferenceLength(set') = |set △ set'| = |set ∪ set'| − |set ∩ set'|
+func (set IntervalSet) SymmetricDifferenceLength(other IntervalSet) int {
+	return sweepLength(set, other, func(inA, inB bool) bool {
+		return inA != inB
+	})
+}
+
+// Jaccard returns the Jaccard similarity |A ∩ B| / |A ∪ B| of the set and other.
+//
+// Returns 0 if both sets are empty.
+//
+// For example:
+//
+//	a = {[0,4)}
+//	b = {[2,6)}
+//	result = 2 / 6
+//
+// Jaccard(set') = |set ∩ set'| / |set ∪ set'|
+func (set IntervalSet) Jaccard(other IntervalSet) float64 {
+	union := set.UnionLength(other)
+	if union == 0 {
+		return 0
+	}
+	return float64(set.IntersectLength(other)) / float64(union)
+}
+
+// OverlapCoefficient returns the overlap coefficient |A ∩ B| / min(|A|, |B|) of the set and other.
+//
+// Returns 0 if either set is empty.
+//
+// For example:
+//
+//	a = {[0,2)}
+//	b = {[0,6)}
+//	result = 1
+//
+// OverlapCoefficient(set') = |set ∩ set'| / min(|set|, |set'|)
+func (set IntervalSet) OverlapCoefficient(other IntervalSet) float64 {
+	smaller := min(set.TotalLength(), other.TotalLength())
+	if smaller == 0 {
+		return 0
+	}
+	return float64(set.IntersectLength(other)) / float64(smaller)
+}
+
+// Histogram splits base into bins equal-width bins and returns the number of covered integers in each.
+//
+// Each bin has width ⌊|base| / bins⌋; the remainder is added to the last bin.
+// Returns nil if bins ≤ 0 or base is empty.
+//
+// For example:
+//
+//	set  = {[0,5)}
+//	base = [0,10), bins = 2
+//	result = [5, 0]
+func (set IntervalSet) Histogram(base IntegerInterval, bins int) []int {
+	if bins <= 0 || base.Length() <= 0 {
+		return nil
+	}
+	width := base.Length() / bins
+	normalized := set.Normalize()
+	result := make([]int, bins)
+	j := 0
+	for b := range bins {
+		bin := IntegerInterval{Start: base.Start + b*width, End: base.Start + (b+1)*width}
+		if b == bins-1 {
+			bin.End = base.End
+		}
+		for j < len(normalized) && normalized[j].End <= bin.Start {
+			j++
+		}
+		for k := j; k < len(normalized) && normalized[k].Start < bin.End; k++ {
+			if intersection, ok := normalized[k].Intersect(bin); ok {
+				result[b] += intersection.Length()
+			}
+		}
+	}
+	return result
+}
+
+// SlidingCoverage slides a window of width w across base in steps of step and returns
+// the covered length within each window, starting with the window at base.Start.
+//
+// Only windows lying entirely within base are reported.
+// Returns nil if w ≤ 0, step ≤ 0 or w > |base|.
+//
+// For example:
+//
+//	set  = {[4,8)}
+//	base = [0,12), w = 4, step = 2
+//	result = [0, 2, 4, 2, 0]
+func (set IntervalSet) SlidingCoverage(w, step int, base IntegerInterval) []int {
+	if w <= 0 || step <= 0 || w > base.Length() {
+		return nil
+	}
+	coveredBefore := coverageCounter(intersectNormalized(set.Normalize(), IntervalSet{base}))
+	var result []int
+	for s := base.Start; s+w <= base.End; s += step {
+		result = append(result, coveredBefore(s+w)-coveredBefore(s))
+	}
+	return result
+}
+
+// DenseRegions returns the positions of base whose local coverage density exceeds threshold,
+// merged into intervals.
+//
+// The density at x is the covered fraction of the centered window
+// [x − ⌊window/2⌋, x − ⌊window/2⌋ + window); only coverage inside base counts, but the fraction
+// is always taken over the full window width, so density falls off near the edges of base.
+// Every position of base is evaluated, so this is O(|base| · log n).
+// Returns nil if window ≤ 0.
+//
+// For example:
+//
+//	set  = {[10,14)}
+//	base = [0,30), window = 4, threshold = 0.5
+//	result = {[11,14)} (the window at 10 is [8,12), only half covered)
+func (set IntervalSet) DenseRegions(window int, threshold float64, base IntegerInterval) IntervalSet {
+	if window <= 0 {
+		return nil
+	}
+	coveredBefore := coverageCounter(intersectNormalized(set.Normalize(), IntervalSet{base}))
+	return IntervalsFromPredicate(base.Start, base.End, func(x int) bool {
+		start := x - window/2
+		covered := coveredBefore(start+window) - coveredBefore(start)
+		return float64(covered)/float64(window) > threshold
+	})
+}
+
+// ResidualLengths returns, for each member of the set, its length after removing exclusions.
+//
+// The result is aligned index-for-index with the set.
+//
+// For example:
+//
+//	set        = {[0,10), [20,25)}
+//	exclusions = {[2,4), [12,30)}
+//	result     = [8, 0]
+//
+// ResidualLengths(X)[i] = |set[i] − X|
+func (set IntervalSet) ResidualLengths(exclusions IntervalSet) []int {
+	exclusions = exclusions.Normalize()
+	result := make([]int, len(set))
+	for i, iv := range set {
+		result[i] = max(iv.Length(), 0) - IntervalSet{iv}.IntersectLength(exclusions)
+	}
+	return result
+}
+
+// CoveredFraction returns the fraction of base covered by the set, in [0,1].
+//
+// Parts of the set outside base are ignored. Returns 0 if base is empty.
+//
+// For example:
+//
+//	set  = {[0,5), [8,20)}
+//	base = [0,10)
+//	result = 0.7
+//
+// CoveredFraction(base) = |set ∩ base| / |base|
+func (set IntervalSet) CoveredFraction(base IntegerInterval) float64 {
+	if base.Length() <= 0 {
+		return 0
+	}
+	return float64(set.IntersectLength(IntervalSet{base})) / float64(base.Length())
+}
+
+// LengthHistogram returns the number of members of each distinct length.
+//
+// Members are counted as they are, without normalization. Returns an empty map for an empty set.
+//
+// For example:
+//
+//	set    = {[0,2), [3,5), [5,10)}
+//	result = {2: 2, 5: 1}
+func (set IntervalSet) LengthHistogram() map[int]int {
+	result := make(map[int]int)
+	for _, iv := range set {
+		result[iv.Length()]++
+	}
+	return result
+}
+
+// MarginalCoverage returns how many new integers iv would add to the set's coverage,
+// without building the union.
+//
+// For example:
+//
+//	set = {[0,5)}
+//	iv  = [3,8)
+//	result = 3
+//
+// MarginalCoverage(iv) = |iv − set|
+func (set IntervalSet) MarginalCoverage(iv IntegerInterval) int {
+	return max(iv.Length(), 0) - IntervalSet{iv}.IntersectLength(set)
+}
+
+// PrefixCoverage returns the coverage growth curve of order:
+// element i is the number of integers covered by order[0], …, order[i].
+//
+// Each interval is inserted incrementally into a normalized set (binary search and splice),
+// rather than re-normalizing every prefix.
+//
+// For example:
+//
+//	order  = [[0,5), [3,8), [1,2), [10,12)]
+//	result = [5, 8, 8, 10]
+//
+// PrefixCoverage(O)[i] = |O[0] ∪ … ∪ O[i]|
+func PrefixCoverage(order []IntegerInterval) []int {
+	result := make([]int, len(order))
+	var covered IntervalSet
+	total := 0
+	for i, iv := range order {
+		if iv.Length() > 0 {
+			// iv と重なるか接する区間は covered[lo:hi]
+			lo := sort.Search(len(covered), func(k int) bool { return covered[k].End >= iv.Start })
+			hi := lo
+			merged := iv
+			for hi < len(covered) && covered[hi].Start <= iv.End {
+				overlap, _ := covered[hi].Intersect(iv)
+				total -= overlap.Length()
+				merged, _ = merged.Merge(covered[hi])
+				hi++
+			}
+			total += iv.Length()
+			covered = slices.Replace(covered, lo, hi, merged)
+		}
+		result[i] = total
+	}
+	return result
+}
+
+// endpoint is a boundary event of an interval belonging to side 0 (a) or 1 (b).
+type endpoint struct {
+	pos   int
+	delta int
+	side  int
+}
+
+// sweepEndpoints returns the endpoints of all non-empty intervals in a and b, sorted by position.
+func sweepEndpoints(a, b IntervalSet) []endpoint {
+	events := make([]endpoint, 0, 2*(len(a)+len(b)))
+	for side, set := range [2]IntervalSet{a, b} {
+		for _, iv := range set {
+			if iv.Length() <= 0 {
+				continue
+			}
+			events = append(events, endpoint{iv.Start, +1, side}, endpoint{iv.End, -1, side})
+		}
+	}
+	slices.SortFunc(events, func(x, y endpoint) int {
+		return cmp.Compare(x.pos, y.pos)
+	})
+	return events
+}
+
+// sweepLength は a, b の端点を一度だけ走査し、keep(inA, inB) が真となる区間の長さを合計する。
+func sweepLength(a, b IntervalSet, keep func(inA, inB bool) bool) int {
+	events := sweepEndpoints(a, b)
+	var depth [2]int
+	total := 0
+	for i, e := range events {
+		depth[e.side] += e.delta
+		if i+1 < len(events) && keep(depth[0] > 0, depth[1] > 0) {
+			total += events[i+1].pos - e.pos
+		}
+	}
+	return total
+}
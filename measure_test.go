@@ -0,0 +1,223 @@
+package interval
+
+import (
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestIntervalSet_UnionLengthIntersectLength(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	for range 500 {
+		a, b := randomSet(r), randomSet(r)
+		if got, want := a.UnionLength(b), a.Union(b).TotalLength(); got != want {
+			t.Fatalf("%v.UnionLength(%v) = %d, want %d", a, b, got, want)
+		}
+		if got, want := a.IntersectLength(b), a.Intersect(b).TotalLength(); got != want {
+			t.Fatalf("%v.IntersectLength(%v) = %d, want %d", a, b, got, want)
+		}
+	}
+}
+
+func TestIntervalSet_DepthIntegral(t *testing.T) {
+	if got := (IntervalSet{{0, 3}, {2, 5}, {7, 8}, {4, 4}}).DepthIntegral(); got != 7 {
+		t.Errorf("DepthIntegral() = %d, want 7", got)
+	}
+	r := rand.New(rand.NewPCG(39, 40))
+	for range 200 {
+		set := randomSet(r)
+		naive := 0
+		for _, iv := range set {
+			naive += iv.Length()
+		}
+		depthSum := 0
+		for x := range 60 {
+			depthSum += len(set.IntervalsCovering(x))
+		}
+		if got := set.DepthIntegral(); got != naive || got != depthSum {
+			t.Fatalf("%v.DepthIntegral() = %d, want %d (sum of depths %d)", set, got, naive, depthSum)
+		}
+	}
+}
+
+func TestIntervalSet_Jaccard(t *testing.T) {
+	tests := []struct {
+		a, b IntervalSet
+		want float64
+	}{
+		{IntervalSet{{0, 4}, {6, 8}}, IntervalSet{{6, 8}, {0, 4}}, 1},
+		{IntervalSet{{0, 4}}, IntervalSet{{4, 8}}, 0},
+		{IntervalSet{{0, 4}}, IntervalSet{{2, 6}}, 2.0 / 6.0},
+		{nil, nil, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.a.Jaccard(tt.b); got != tt.want {
+			t.Errorf("%v.Jaccard(%v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestIntervalSet_OverlapCoefficient(t *testing.T) {
+	tests := []struct {
+		a, b IntervalSet
+		want float64
+	}{
+		{IntervalSet{{1, 3}}, IntervalSet{{0, 10}}, 1},
+		{IntervalSet{{0, 4}}, IntervalSet{{4, 8}}, 0},
+		{IntervalSet{{0, 4}}, IntervalSet{{2, 10}}, 0.5},
+		{IntervalSet{{0, 4}}, nil, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.a.OverlapCoefficient(tt.b); got != tt.want {
+			t.Errorf("%v.OverlapCoefficient(%v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestIntervalSet_Histogram(t *testing.T) {
+	tests := []struct {
+		set  IntervalSet
+		base IntegerInterval
+		bins int
+		want []int
+	}{
+		{IntervalSet{{0, 5}}, IntegerInterval{0, 10}, 2, []int{5, 0}},
+		{IntervalSet{{3, 8}}, IntegerInterval{0, 10}, 2, []int{2, 3}},
+		{IntervalSet{{0, 2}, {8, 11}, {3, 4}}, IntegerInterval{0, 11}, 3, []int{2, 1, 3}}, // 幅3,3,5
+		{IntervalSet{{-5, 20}}, IntegerInterval{0, 10}, 5, []int{2, 2, 2, 2, 2}},
+		{IntervalSet{{0, 5}}, IntegerInterval{0, 10}, 0, nil},
+	}
+	for _, tt := range tests {
+		if got := tt.set.Histogram(tt.base, tt.bins); !slices.Equal(got, tt.want) {
+			t.Errorf("%v.Histogram(%v, %d) = %v, want %v", tt.set, tt.base, tt.bins, got, tt.want)
+		}
+	}
+}
+
+func TestIntervalSet_SlidingCoverage(t *testing.T) {
+	tests := []struct {
+		set     IntervalSet
+		w, step int
+		base    IntegerInterval
+		want    []int
+	}{
+		// 局所的な塊は山になる
+		{IntervalSet{{4, 8}}, 4, 2, IntegerInterval{0, 12}, []int{0, 2, 4, 2, 0}},
+		{IntervalSet{{5, 6}, {4, 5}, {6, 8}}, 4, 2, IntegerInterval{0, 13}, []int{0, 2, 4, 2, 0}},
+		// 一様な被覆は平らになる
+		{IntervalSet{{0, 1}, {2, 3}, {4, 5}, {6, 7}, {8, 9}}, 2, 1, IntegerInterval{0, 8}, []int{1, 1, 1, 1, 1, 1, 1}},
+		{IntervalSet{{0, 10}}, 0, 1, IntegerInterval{0, 8}, nil},
+		{IntervalSet{{0, 10}}, 2, 0, IntegerInterval{0, 8}, nil},
+		{IntervalSet{{0, 10}}, 9, 1, IntegerInterval{0, 8}, nil},
+	}
+	for _, tt := range tests {
+		if got := tt.set.SlidingCoverage(tt.w, tt.step, tt.base); !slices.Equal(got, tt.want) {
+			t.Errorf("%v.SlidingCoverage(%d, %d, %v) = %v, want %v", tt.set, tt.w, tt.step, tt.base, got, tt.want)
+		}
+	}
+}
+
+func TestIntervalSet_DenseRegions(t *testing.T) {
+	// [20,26) は密な塊、[0,12) は 4 つおきに 1 点だけの疎な領域
+	set := IntervalSet{{0, 1}, {4, 5}, {8, 9}, {20, 22}, {22, 24}, {25, 26}}
+	base := IntegerInterval{0, 40}
+	if got, want := set.DenseRegions(4, 0.5, base), (IntervalSet{{21, 25}}); !slices.Equal(got, want) {
+		t.Errorf("DenseRegions(4, 0.5) = %v, want %v", got, want)
+	}
+	if got, want := set.DenseRegions(4, 0.2, base), (IntervalSet{{0, 11}, {19, 28}}); !slices.Equal(got, want) {
+		t.Errorf("DenseRegions(4, 0.2) = %v, want %v", got, want)
+	}
+	if got := set.DenseRegions(0, 0.5, base); got != nil {
+		t.Errorf("DenseRegions(0) = %v, want nil", got)
+	}
+}
+
+func TestIntervalSet_ResidualLengths(t *testing.T) {
+	set := IntervalSet{{0, 10}, {20, 25}, {40, 45}, {1, 5}}
+	exclusions := IntervalSet{{2, 4}, {12, 30}, {3, 4}}
+	if got, want := set.ResidualLengths(exclusions), []int{8, 0, 5, 2}; !slices.Equal(got, want) {
+		t.Errorf("ResidualLengths(%v) = %v, want %v", exclusions, got, want)
+	}
+}
+
+func TestIntervalSet_CoveredFraction(t *testing.T) {
+	tests := []struct {
+		set  IntervalSet
+		base IntegerInterval
+		want float64
+	}{
+		{IntervalSet{{0, 5}}, IntegerInterval{0, 10}, 0.5},
+		{IntervalSet{{-10, 5}, {8, 20}}, IntegerInterval{0, 10}, 0.7},
+		{IntervalSet{{0, 4}, {2, 6}}, IntegerInterval{0, 6}, 1},
+		{IntervalSet{{0, 5}}, IntegerInterval{3, 3}, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.set.CoveredFraction(tt.base); got != tt.want {
+			t.Errorf("%v.CoveredFraction(%v) = %v, want %v", tt.set, tt.base, got, tt.want)
+		}
+	}
+}
+
+func TestPrefixCoverage(t *testing.T) {
+	order := []IntegerInterval{{0, 5}, {3, 8}, {1, 2}, {10, 12}, {8, 10}, {-2, 20}}
+	if got, want := PrefixCoverage(order), []int{5, 8, 8, 10, 12, 22}; !slices.Equal(got, want) {
+		t.Errorf("PrefixCoverage(%v) = %v, want %v", order, got, want)
+	}
+
+	r := rand.New(rand.NewPCG(19, 20))
+	for range 200 {
+		set := randomSet(r)
+		got := PrefixCoverage(set)
+		for i := range set {
+			if want := set[:i+1].TotalLength(); got[i] != want {
+				t.Fatalf("PrefixCoverage(%v)[%d] = %d, want %d", set, i, got[i], want)
+			}
+		}
+	}
+}
+
+func TestIntervalSet_MarginalCoverage(t *testing.T) {
+	set := IntervalSet{{0, 5}, {2, 4}, {10, 12}}
+	tests := []struct {
+		iv   IntegerInterval
+		want int
+	}{
+		{IntegerInterval{3, 8}, 3},
+		{IntegerInterval{1, 4}, 0},
+		{IntegerInterval{20, 25}, 5},
+		{IntegerInterval{4, 11}, 5},
+	}
+	for _, tt := range tests {
+		if got := set.MarginalCoverage(tt.iv); got != tt.want {
+			t.Errorf("MarginalCoverage(%v) = %d, want %d", tt.iv, got, tt.want)
+		}
+	}
+}
+
+func TestIntervalSet_SymmetricDifferenceLength(t *testing.T) {
+	r := rand.New(rand.NewPCG(21, 22))
+	for range 500 {
+		a, b := randomSet(r), randomSet(r)
+		got := a.SymmetricDifferenceLength(b)
+		if want := a.SymmetricDifference(b).TotalLength(); got != want {
+			t.Fatalf("%v.SymmetricDifferenceLength(%v) = %d, want %d", a, b, got, want)
+		}
+		if want := a.UnionLength(b) - a.IntersectLength(b); got != want {
+			t.Fatalf("%v.SymmetricDifferenceLength(%v) = %d, want |A∪B|−|A∩B| = %d", a, b, got, want)
+		}
+		if self := a.SymmetricDifferenceLength(a); self != 0 {
+			t.Fatalf("%v.SymmetricDifferenceLength(itself) = %d", a, self)
+		}
+	}
+}
+
+func TestIntervalSet_LengthHistogram(t *testing.T) {
+	got := IntervalSet{{0, 2}, {3, 5}, {5, 10}, {1, 3}}.LengthHistogram()
+	if want := map[int]int{2: 3, 5: 1}; !maps.Equal(got, want) {
+		t.Errorf("LengthHistogram() = %v, want %v", got, want)
+	}
+	if got := IntervalSet(nil).LengthHistogram(); got == nil || len(got) != 0 {
+		t.Errorf("LengthHistogram() of empty set = %#v, want empty map", got)
+	}
+}
@@ -0,0 +1,58 @@
+package interval
+
+import (
+	"fmt"
+	"slices"
+)
+
+// MutableIntervalSet is an IntervalSet that is edited in place and supports undo.
+//
+// The covered region is always kept normalized.
+// Snapshot records the current state and returns its version id; Restore rolls back to it.
+// The zero value is an empty set ready to use.
+type MutableIntervalSet struct {
+	set      IntervalSet
+	versions []IntervalSet
+}
+
+// NewMutableIntervalSet returns a MutableIntervalSet initially covering set.
+func NewMutableIntervalSet(set IntervalSet) *MutableIntervalSet {
+	return &MutableIntervalSet{set: set.Normalize()}
+}
+
+// Set returns a copy of the current covered region.
+func (m *MutableIntervalSet) Set() IntervalSet {
+	return slices.Clone(m.set)
+}
+
+// AddInterval adds iv to the covered region.
+//
+// AddInterval(iv): set ← set ∪ {iv}
+func (m *MutableIntervalSet) AddInterval(iv IntegerInterval) {
+	m.set = m.set.Union(IntervalSet{iv})
+}
+
+// RemoveInterval removes iv from the covered region.
+//
+// RemoveInterval(iv): set ← set − iv
+func (m *MutableIntervalSet) RemoveInterval(iv IntegerInterval) {
+	m.set = m.set.Subtract(iv)
+}
+
+// Snapshot records the current state and returns its version id.
+func (m *MutableIntervalSet) Snapshot() int {
+	m.versions = append(m.versions, slices.Clone(m.set))
+	return len(m.versions) - 1
+}
+
+// Restore rolls the covered region back to the state recorded by Snapshot under version.
+//
+// Snapshots are kept after a Restore, so it is possible to move between versions freely.
+// Returns an error if version is unknown.
+func (m *MutableIntervalSet) Restore(version int) error {
+	if version < 0 || version >= len(m.versions) {
+		return fmt.Errorf("unknown version %d", version)
+	}
+	m.set = slices.Clone(m.versions[version])
+	return nil
+}
@@ -0,0 +1,34 @@
+package interval
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMutableIntervalSet_SnapshotRestore(t *testing.T) {
+	var m MutableIntervalSet
+	m.AddInterval(IntegerInterval{0, 3})
+	m.AddInterval(IntegerInterval{5, 8})
+	m.AddInterval(IntegerInterval{3, 4})
+	v := m.Snapshot()
+	want := IntervalSet{{0, 4}, {5, 8}}
+	if got := m.Set(); !slices.Equal(got, want) {
+		t.Fatalf("Set() = %v, want %v", got, want)
+	}
+
+	m.RemoveInterval(IntegerInterval{1, 6})
+	m.AddInterval(IntegerInterval{10, 12})
+	if got, after := m.Set(), (IntervalSet{{0, 1}, {6, 8}, {10, 12}}); !slices.Equal(got, after) {
+		t.Fatalf("Set() = %v, want %v", got, after)
+	}
+
+	if err := m.Restore(v); err != nil {
+		t.Fatal(err)
+	}
+	if got := m.Set(); !slices.Equal(got, want) {
+		t.Errorf("after Restore, Set() = %v, want %v", got, want)
+	}
+	if err := m.Restore(v + 1); err == nil {
+		t.Error("expected error for unknown version")
+	}
+}
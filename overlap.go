@@ -0,0 +1,129 @@
+package interval
+
+import (
+	"cmp"
+	"slices"
+)
+
+// IndexedOverlap is the non-empty intersection of two intervals identified by their indices.
+type IndexedOverlap struct {
+	I, J         int
+	Intersection IntegerInterval
+}
+
+// SelfIntersections returns every pair of members of the set that overlap, with their intersection.
+//
+// Each pair is reported once with I < J, sorted by (I, J).
+// Adjacent intervals do not overlap and are not reported.
+// A sweep line over the sorted Starts makes this O(n log n + k) for k reported pairs.
+//
+// For example:
+//
+//	set    = {[0,10), [2,8), [4,6)}
+//	result = {0,1,[2,8)}, {0,2,[4,6)}, {1,2,[4,6)}
+func (set IntervalSet) SelfIntersections() []IndexedOverlap {
+	order := make([]int, 0, len(set))
+	for i, iv := range set {
+		if !iv.IsEmpty() && iv.IsValid() {
+			order = append(order, i)
+		}
+	}
+	slices.SortFunc(order, func(a, b int) int {
+		return set[a].Compare(set[b])
+	})
+
+	var result []IndexedOverlap
+	var active []int // 現在の位置をまだ覆っている区間
+	for _, j := range order {
+		start := set[j].Start
+		active = slices.DeleteFunc(active, func(i int) bool {
+			return set[i].End <= start
+		})
+		for _, i := range active {
+			intersection, _ := set[i].Intersect(set[j])
+			result = append(result, IndexedOverlap{I: min(i, j), J: max(i, j), Intersection: intersection})
+		}
+		active = append(active, j)
+	}
+	slices.SortFunc(result, func(a, b IndexedOverlap) int {
+		return cmp.Or(a.I-b.I, a.J-b.J)
+	})
+	return result
+}
+
+// IntersectTagged returns the raw, unnormalized intersections between members of the set (I)
+// and members of other (J), in iteration order.
+//
+// Unlike Intersect, each entry records which pair produced it, so the same region
+// may appear several times.
+//
+// For example:
+//
+//	a = {[0,5), [2,6)}
+//	b = {[3,4)}
+//	result = {0,0,[3,4)}, {1,0,[3,4)}
+func (set IntervalSet) IntersectTagged(other IntervalSet) []IndexedOverlap {
+	var result []IndexedOverlap
+	for i, iv1 := range set {
+		for j, iv2 := range other {
+			if intersection, ok := iv1.Intersect(iv2); ok {
+				result = append(result, IndexedOverlap{I: i, J: j, Intersection: intersection})
+			}
+		}
+	}
+	return result
+}
+
+// OverlapSummary counts how many members of another set overlap the member at Index,
+// and their total overlap length.
+type OverlapSummary struct {
+	Index         int
+	OverlapCount  int
+	OverlapLength int
+}
+
+// OverlapReport returns, for each member of the set, how many members of other it overlaps
+// and the summed length of those overlaps.
+//
+// The result is aligned index-for-index with the set. Members of other are taken as they are,
+// so if two of them overlap each other, their common part is counted twice.
+//
+// For example:
+//
+//	a = {[0,10), [20,25)}
+//	b = {[2,4), [6,8)}
+//	result = {0,2,4}, {1,0,0}
+func (set IntervalSet) OverlapReport(other IntervalSet) []OverlapSummary {
+	result := make([]OverlapSummary, len(set))
+	for i, iv1 := range set {
+		result[i].Index = i
+		for _, iv2 := range other {
+			if intersection, ok := iv1.Intersect(iv2); ok {
+				result[i].OverlapCount++
+				result[i].OverlapLength += intersection.Length()
+			}
+		}
+	}
+	return result
+}
+
+// IsAdjacentTo reports whether some member of the set touches some member of other
+// without overlapping it, i.e. the two sets meet at a seam.
+//
+// For example:
+//
+//	a = {[0,3)}, b = {[3,6)} → true
+//	a = {[0,4)}, b = {[3,6)} → false
+//	a = {[0,2)}, b = {[3,6)} → false
+//
+// IsAdjacentTo(set') ⇔ ∃ a ∈ set, b ∈ set', a.IsAdjacent(b) ∧ ¬a.Overlaps(b)
+func (set IntervalSet) IsAdjacentTo(other IntervalSet) bool {
+	for _, a := range set {
+		for _, b := range other {
+			if a.IsAdjacent(b) && !a.Overlaps(b) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+package interval
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestIntervalSet_SelfIntersections(t *testing.T) {
+	set := IntervalSet{{4, 6}, {0, 10}, {2, 8}, {10, 12}}
+	want := []IndexedOverlap{
+		{0, 1, IntegerInterval{4, 6}},
+		{0, 2, IntegerInterval{4, 6}},
+		{1, 2, IntegerInterval{2, 8}},
+	}
+	if got := set.SelfIntersections(); !slices.Equal(got, want) {
+		t.Errorf("SelfIntersections() = %v, want %v", got, want)
+	}
+	if got := (IntervalSet{{0, 2}, {2, 4}}).SelfIntersections(); len(got) != 0 {
+		t.Errorf("adjacent intervals reported as intersecting: %v", got)
+	}
+}
+
+func TestIntervalSet_IntersectTagged(t *testing.T) {
+	a := IntervalSet{{0, 5}, {2, 6}, {10, 12}}
+	b := IntervalSet{{3, 4}, {5, 11}}
+	want := []IndexedOverlap{
+		{0, 0, IntegerInterval{3, 4}},
+		{1, 0, IntegerInterval{3, 4}},
+		{1, 1, IntegerInterval{5, 6}},
+		{2, 1, IntegerInterval{10, 11}},
+	}
+	if got := a.IntersectTagged(b); !slices.Equal(got, want) {
+		t.Errorf("IntersectTagged = %v, want %v", got, want)
+	}
+}
+
+func TestIntervalSet_OverlapReport(t *testing.T) {
+	a := IntervalSet{{0, 10}, {20, 25}, {5, 7}}
+	b := IntervalSet{{2, 4}, {6, 8}, {10, 20}}
+	want := []OverlapSummary{
+		{0, 2, 4},
+		{1, 0, 0}, // [20,25) は [10,20) に接するだけ
+		{2, 1, 1},
+	}
+	if got := a.OverlapReport(b); !slices.Equal(got, want) {
+		t.Errorf("OverlapReport = %v, want %v", got, want)
+	}
+	if got := a.OverlapReport(nil); !slices.Equal(got, []OverlapSummary{{0, 0, 0}, {1, 0, 0}, {2, 0, 0}}) {
+		t.Errorf("OverlapReport(nil) = %v", got)
+	}
+}
+
+func TestIntervalSet_IsAdjacentTo(t *testing.T) {
+	tests := []struct {
+		a, b IntervalSet
+		want bool
+	}{
+		{IntervalSet{{0, 3}}, IntervalSet{{3, 6}}, true},
+		{IntervalSet{{10, 12}, {0, 3}}, IntervalSet{{6, 10}}, true},
+		{IntervalSet{{0, 4}}, IntervalSet{{3, 6}}, false},
+		{IntervalSet{{0, 2}}, IntervalSet{{3, 6}}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.a.IsAdjacentTo(tt.b); got != tt.want {
+			t.Errorf("%v.IsAdjacentTo(%v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
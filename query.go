@@ -0,0 +1,352 @@
+package interval
+
+import (
+	"cmp"
+	"slices"
+	"sort"
+)
+
+// EnclosingInterval returns the merged (normalized) interval of the set containing n.
+//
+// Returns false if n is not covered.
+//
+// For example:
+//
+//	set = {[0,2), [2,5), [8,9)}
+//	n = 3 → [0,5)
+//	n = 6 → false
+func (set IntervalSet) EnclosingInterval(n int) (IntegerInterval, bool) {
+	normalized := set.Normalize()
+	i := firstEndAfter(normalized, n)
+	if i < len(normalized) && normalized[i].Contains(n) {
+		return normalized[i], true
+	}
+	return IntegerInterval{}, false
+}
+
+// IntervalsCovering returns every member of the set that contains n, in their original order.
+//
+// For example:
+//
+//	set = {[0,5), [2,8), [6,9)}
+//	n = 3 → {[0,5), [2,8)}
+//
+// IntervalsCovering(n) = { iv ∈ set | n ∈ iv }
+func (set IntervalSet) IntervalsCovering(n int) IntervalSet {
+	var result IntervalSet
+	for _, iv := range set {
+		if iv.Contains(n) {
+			result = append(result, iv)
+		}
+	}
+	return result
+}
+
+// RegionCount returns the number of merged regions of the set: the length of the normalized
+// set, ignoring empty members.
+//
+// For example:
+//
+//	set    = {[0,2), [2,4), [10,12)}
+//	result = 2
+func (set IntervalSet) RegionCount() int {
+	return len(set.canonical())
+}
+
+// NthRegion returns the n-th (0-based) merged region of the set, counting the normalized
+// intervals from the left. Empty members are ignored.
+//
+// Returns false if n is negative or there are not that many regions.
+//
+// For example:
+//
+//	set = {[5,8), [0,2), [1,3)}
+//	n = 0 → [0,3), n = 1 → [5,8), n = 2 → false
+func (set IntervalSet) NthRegion(n int) (IntegerInterval, bool) {
+	regions := set.canonical()
+	if n < 0 || n >= len(regions) {
+		return IntegerInterval{}, false
+	}
+	return regions[n], true
+}
+
+// FirstUncovered returns the smallest integer n ≥ from that is not covered by the set.
+//
+// For example:
+//
+//	set = {[0,5), [5,7), [9,10)}
+//	from = 2 → 7
+//	from = 8 → 8
+//
+// FirstUncovered(from) = min{ n ≥ from | ¬ContainsPoint(n) }
+func (set IntervalSet) FirstUncovered(from int) int {
+	normalized := set.Normalize()
+	i := firstEndAfter(normalized, from)
+	if i < len(normalized) && normalized[i].Contains(from) {
+		return normalized[i].End
+	}
+	return from
+}
+
+// FirstCovered returns the smallest integer n ≥ from that is covered by the set.
+//
+// Returns false if nothing at or after from is covered.
+//
+// For example:
+//
+//	set = {[5,8)}
+//	from = 0 → 5
+//	from = 6 → 6
+//	from = 8 → false
+//
+// FirstCovered(from) = min{ n ≥ from | ContainsPoint(n) }
+func (set IntervalSet) FirstCovered(from int) (int, bool) {
+	normalized := set.Normalize()
+	for i := firstEndAfter(normalized, from); i < len(normalized); i++ {
+		if !normalized[i].IsEmpty() {
+			return max(from, normalized[i].Start), true
+		}
+	}
+	return 0, false
+}
+
+// firstEndAfter は正規化済み集合 normalized のうち End > n となる最初の区間の添字を二分探索で返す。
+func firstEndAfter(normalized IntervalSet, n int) int {
+	return sort.Search(len(normalized), func(i int) bool {
+		return normalized[i].End > n
+	})
+}
+
+// NextGap returns the first gap of the set within base (see Complement) whose Start ≥ after.
+//
+// Returns false if there is no such gap.
+//
+// For example:
+//
+//	set  = {[2,4), [6,8)}
+//	base = [0,10)
+//	after = 3 → [4,6)
+//	after = 9 → false
+func (set IntervalSet) NextGap(after int, base IntegerInterval) (IntegerInterval, bool) {
+	for _, gap := range set.Complement(base) {
+		if gap.Start >= after {
+			return gap, true
+		}
+	}
+	return IntegerInterval{}, false
+}
+
+// PrevGap returns the last gap of the set within base (see Complement) whose End ≤ before.
+//
+// Returns false if there is no such gap.
+//
+// For example:
+//
+//	set  = {[2,4), [6,8)}
+//	base = [0,10)
+//	before = 7 → [4,6)
+//	before = 1 → false
+func (set IntervalSet) PrevGap(before int, base IntegerInterval) (IntegerInterval, bool) {
+	gaps := set.Complement(base)
+	for i := len(gaps) - 1; i >= 0; i-- {
+		if gaps[i].End <= before {
+			return gaps[i], true
+		}
+	}
+	return IntegerInterval{}, false
+}
+
+// LongestRun returns the longest merged (normalized) interval of the set.
+// Ties resolve to the earliest.
+//
+// Returns false if the set covers nothing.
+//
+// For example:
+//
+//	set    = {[0,2), [2,4), [6,10), [12,13)}
+//	result = [0,4)
+func (set IntervalSet) LongestRun() (IntegerInterval, bool) {
+	return longest(set.Normalize())
+}
+
+// LongestGap returns the longest gap of the set within base (see Complement).
+// Ties resolve to the earliest.
+//
+// Returns false if there is no gap.
+//
+// For example:
+//
+//	set    = {[6,7), [8,10)}
+//	base   = [0,10)
+//	result = [0,6)
+func (set IntervalSet) LongestGap(base IntegerInterval) (IntegerInterval, bool) {
+	return longest(set.Complement(base))
+}
+
+// LargestGaps returns the k longest gaps of the set within base (see Complement),
+// in position order. Ties in length resolve to the earliest.
+//
+// Returns all gaps if there are at most k, and nil if k ≤ 0.
+//
+// For example:
+//
+//	set  = {[5,7), [10,12)}
+//	base = [0,20), k = 2 → gaps [0,5), [7,10), [12,20) → {[0,5), [12,20)}
+func (set IntervalSet) LargestGaps(base IntegerInterval, k int) IntervalSet {
+	if k <= 0 {
+		return nil
+	}
+	gaps := set.ComplementFast(base)
+	if len(gaps) <= k {
+		return gaps
+	}
+	order := make([]int, len(gaps))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return cmp.Compare(gaps[b].Length(), gaps[a].Length())
+	})
+	order = order[:k]
+	slices.Sort(order)
+	result := make(IntervalSet, k)
+	for i, j := range order {
+		result[i] = gaps[j]
+	}
+	return result
+}
+
+// SignificantGaps returns the gaps of the set within base (see Complement) whose length is
+// at least minRatio·|base|, in position order, ignoring the tiny ones.
+//
+// For example:
+//
+//	set  = {[10,11), [12,13), [14,60)}
+//	base = [0,100), minRatio = 0.1
+//	result = {[0,10), [60,100)}
+func (set IntervalSet) SignificantGaps(base IntegerInterval, minRatio float64) IntervalSet {
+	minLen := minRatio * float64(base.Length())
+	return slices.DeleteFunc(set.ComplementFast(base), func(gap IntegerInterval) bool {
+		return float64(gap.Length()) < minLen
+	})
+}
+
+// Boundaries returns the distinct Start and End values of all members, sorted.
+//
+// Returns nil for an empty set.
+//
+// For example:
+//
+//	set    = {[0,3), [2,5), [3,5)}
+//	result = [0, 2, 3, 5]
+func (set IntervalSet) Boundaries() []int {
+	if len(set) == 0 {
+		return nil
+	}
+	result := make([]int, 0, 2*len(set))
+	for _, iv := range set {
+		result = append(result, iv.Start, iv.End)
+	}
+	slices.Sort(result)
+	return slices.Compact(result)
+}
+
+// BestWindow returns the window of length w within base that covers the most of the set,
+// together with that covered length. Ties resolve to the earliest window.
+//
+// The covered length is piecewise linear in the window position, so only positions where
+// a window edge meets an endpoint of the set (or of base) need to be evaluated.
+// Returns ([0,0), 0) if w ≤ 0 or w > |base|.
+//
+// For example:
+//
+//	set  = {[0,1), [10,12), [13,15)}
+//	base = [0,20), w = 5
+//	result = [10,15), 4
+func (set IntervalSet) BestWindow(w int, base IntegerInterval) (IntegerInterval, int) {
+	if w <= 0 || w > base.Length() {
+		return IntegerInterval{}, 0
+	}
+	normalized := intersectNormalized(set.Normalize(), IntervalSet{base})
+	coveredBefore := coverageCounter(normalized)
+
+	last := base.End - w
+	candidates := []int{base.Start, last}
+	for _, iv := range normalized {
+		candidates = append(candidates, iv.Start, iv.End-w)
+	}
+	slices.Sort(candidates)
+
+	best, bestCovered := IntegerInterval{}, -1
+	for _, s := range candidates {
+		if s < base.Start || s > last {
+			continue
+		}
+		if covered := coveredBefore(s+w) - coveredBefore(s); covered > bestCovered {
+			best, bestCovered = IntegerInterval{Start: s, End: s + w}, covered
+		}
+	}
+	return best, bestCovered
+}
+
+// BestFill returns where to place an interval of length size within base to cover the most
+// of the set's gaps, together with the gap length it would newly cover. Ties resolve to the earliest.
+//
+// This is BestWindow over the gaps within base, so the candidates are windows aligned to
+// the start or the end of a gap. Returns ([0,0), 0) if size ≤ 0 or size > |base|.
+//
+// For example:
+//
+//	set  = {[2,3), [5,10)}
+//	base = [0,20), size = 4
+//	result = [10,14), 4
+func (set IntervalSet) BestFill(base IntegerInterval, size int) (IntegerInterval, int) {
+	return set.ComplementFast(base).BestWindow(size, base)
+}
+
+// coverageCounter は正規化済み集合について、x 未満の被覆長を O(log n) で返す関数を作る。
+func coverageCounter(normalized IntervalSet) func(x int) int {
+	prefix := make([]int, len(normalized)+1)
+	for i, iv := range normalized {
+		prefix[i+1] = prefix[i] + iv.Length()
+	}
+	return func(x int) int {
+		i := firstEndAfter(normalized, x)
+		if i < len(normalized) && normalized[i].Start < x {
+			return prefix[i] + x - normalized[i].Start
+		}
+		return prefix[i]
+	}
+}
+
+// longest は最も長い非空の区間を返す（同じ長さなら先にあるもの）。
+func longest(set IntervalSet) (IntegerInterval, bool) {
+	var best IntegerInterval
+	for _, iv := range set {
+		if iv.Length() > best.Length() {
+			best = iv
+		}
+	}
+	return best, best.Length() > 0
+}
+
+// SnapToCovered returns the merged (normalized) region of the set that overlaps iv the most.
+// Ties resolve to the earliest region.
+//
+// Returns false if iv overlaps no covered region.
+//
+// For example:
+//
+//	set = {[0,4), [6,12)}
+//	iv  = [3,8) → [6,12)
+//	iv  = [4,6) → false
+func (set IntervalSet) SnapToCovered(iv IntegerInterval) (IntegerInterval, bool) {
+	var best IntegerInterval
+	bestOverlap := 0
+	for _, region := range set.Normalize() {
+		if intersection, ok := region.Intersect(iv); ok && intersection.Length() > bestOverlap {
+			best, bestOverlap = region, intersection.Length()
+		}
+	}
+	return best, bestOverlap > 0
+}
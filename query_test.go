@@ -0,0 +1,281 @@
+package interval
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestIntervalSet_EnclosingInterval(t *testing.T) {
+	set := IntervalSet{{2, 5}, {8, 9}, {0, 2}}
+	tests := []struct {
+		n    int
+		want IntegerInterval
+		ok   bool
+	}{
+		{3, IntegerInterval{0, 5}, true},
+		{0, IntegerInterval{0, 5}, true},
+		{8, IntegerInterval{8, 9}, true},
+		{5, IntegerInterval{}, false},
+		{9, IntegerInterval{}, false},
+		{-1, IntegerInterval{}, false},
+	}
+	for _, tt := range tests {
+		if got, ok := set.EnclosingInterval(tt.n); got != tt.want || ok != tt.ok {
+			t.Errorf("EnclosingInterval(%d) = %v, %v, want %v, %v", tt.n, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestIntervalSet_NextGapPrevGap(t *testing.T) {
+	set := IntervalSet{{2, 4}, {6, 8}}
+	base := IntegerInterval{0, 10}
+	next := []struct {
+		after int
+		want  IntegerInterval
+		ok    bool
+	}{
+		{3, IntegerInterval{4, 6}, true},
+		{0, IntegerInterval{0, 2}, true},
+		{7, IntegerInterval{8, 10}, true},
+		{9, IntegerInterval{}, false},
+	}
+	for _, tt := range next {
+		if got, ok := set.NextGap(tt.after, base); got != tt.want || ok != tt.ok {
+			t.Errorf("NextGap(%d) = %v, %v, want %v, %v", tt.after, got, ok, tt.want, tt.ok)
+		}
+	}
+	prev := []struct {
+		before int
+		want   IntegerInterval
+		ok     bool
+	}{
+		{7, IntegerInterval{4, 6}, true},
+		{10, IntegerInterval{8, 10}, true},
+		{1, IntegerInterval{}, false},
+	}
+	for _, tt := range prev {
+		if got, ok := set.PrevGap(tt.before, base); got != tt.want || ok != tt.ok {
+			t.Errorf("PrevGap(%d) = %v, %v, want %v, %v", tt.before, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestIntervalSet_BestFill(t *testing.T) {
+	// [0,2) と [3,5) の小さな隙間をまたぐより、[10,20) の中に置く方が多く埋まる
+	set := IntervalSet{{2, 3}, {5, 10}}
+	if got, gained := set.BestFill(IntegerInterval{0, 20}, 4); got != (IntegerInterval{10, 14}) || gained != 4 {
+		t.Errorf("BestFill(4) = %v, %d, want [10,14), 4", got, gained)
+	}
+	if got, gained := set.BestFill(IntegerInterval{0, 10}, 5); got != (IntegerInterval{0, 5}) || gained != 4 {
+		t.Errorf("BestFill(5) = %v, %d, want [0,5), 4", got, gained)
+	}
+	if _, gained := (IntervalSet{{0, 10}}).BestFill(IntegerInterval{0, 10}, 3); gained != 0 {
+		t.Errorf("BestFill of full cover gained %d", gained)
+	}
+
+	r := rand.New(rand.NewPCG(31, 32))
+	for range 200 {
+		set, size, base := randomSet(r), 1+r.IntN(10), IntegerInterval{0, 50}
+		got, gained := set.BestFill(base, size)
+		want := 0
+		for s := base.Start; s+size <= base.End; s++ {
+			want = max(want, set.MarginalCoverage(IntegerInterval{s, s + size}))
+		}
+		if gained != want || set.MarginalCoverage(got) != gained {
+			t.Fatalf("%v.BestFill(%d) = %v, %d, want gain %d", set, size, got, gained, want)
+		}
+	}
+}
+
+func TestIntervalSet_LargestGaps(t *testing.T) {
+	set := IntervalSet{{5, 7}, {10, 12}} // 隙間の長さは 5, 3, 8
+	base := IntegerInterval{0, 20}
+	tests := []struct {
+		k    int
+		want IntervalSet
+	}{
+		{2, IntervalSet{{0, 5}, {12, 20}}},
+		{1, IntervalSet{{12, 20}}},
+		{5, IntervalSet{{0, 5}, {7, 10}, {12, 20}}},
+		{0, nil},
+	}
+	for _, tt := range tests {
+		if got := set.LargestGaps(base, tt.k); !slices.Equal(got, tt.want) {
+			t.Errorf("LargestGaps(%v, %d) = %v, want %v", base, tt.k, got, tt.want)
+		}
+	}
+	// 同じ長さなら先にある隙間を選ぶ
+	if got, want := (IntervalSet{{2, 4}, {6, 8}}).LargestGaps(IntegerInterval{0, 10}, 2), (IntervalSet{{0, 2}, {4, 6}}); !slices.Equal(got, want) {
+		t.Errorf("LargestGaps tie = %v, want %v", got, want)
+	}
+}
+
+func TestIntervalSet_SignificantGaps(t *testing.T) {
+	// 長さ 30 の隙間がひとつと、長さ 1〜9 の小さな隙間がいくつか
+	set := IntervalSet{{0, 10}, {11, 12}, {14, 50}, {90, 91}, {100, 109}, {59, 60}}
+	base := IntegerInterval{0, 100}
+	if got, want := set.SignificantGaps(base, 0.1), (IntervalSet{{60, 90}}); !slices.Equal(got, want) {
+		t.Errorf("SignificantGaps(0.1) = %v, want %v", got, want)
+	}
+	if got, want := set.SignificantGaps(base, 0.09), (IntervalSet{{50, 59}, {60, 90}, {91, 100}}); !slices.Equal(got, want) {
+		t.Errorf("SignificantGaps(0.09) = %v, want %v", got, want)
+	}
+	if got := set.SignificantGaps(base, 0.5); len(got) != 0 {
+		t.Errorf("SignificantGaps(0.5) = %v, want none", got)
+	}
+}
+
+func TestIntervalSet_LongestRunLongestGap(t *testing.T) {
+	set := IntervalSet{{6, 10}, {0, 2}, {2, 4}, {12, 13}}
+	if got, ok := set.LongestRun(); !ok || got != (IntegerInterval{0, 4}) {
+		t.Errorf("LongestRun() = %v, %v, want [0,4)", got, ok)
+	}
+	if _, ok := IntervalSet(nil).LongestRun(); ok {
+		t.Error("LongestRun() of empty set reported ok")
+	}
+
+	gappy := IntervalSet{{6, 7}, {8, 10}}
+	if got, ok := gappy.LongestGap(IntegerInterval{0, 10}); !ok || got != (IntegerInterval{0, 6}) {
+		t.Errorf("LongestGap() = %v, %v, want [0,6)", got, ok)
+	}
+	if got, ok := gappy.LongestGap(IntegerInterval{5, 10}); !ok || got != (IntegerInterval{5, 6}) {
+		t.Errorf("LongestGap() = %v, %v, want [5,6)", got, ok)
+	}
+	if _, ok := (IntervalSet{{0, 10}}).LongestGap(IntegerInterval{0, 10}); ok {
+		t.Error("LongestGap() of full cover reported ok")
+	}
+}
+
+func TestIntervalSet_FirstUncovered(t *testing.T) {
+	set := IntervalSet{{5, 7}, {0, 5}, {9, 10}}
+	tests := []struct{ from, want int }{
+		{2, 7}, {8, 8}, {9, 10}, {-3, -3}, {12, 12},
+	}
+	for _, tt := range tests {
+		if got := set.FirstUncovered(tt.from); got != tt.want {
+			t.Errorf("FirstUncovered(%d) = %d, want %d", tt.from, got, tt.want)
+		}
+	}
+}
+
+func TestIntervalSet_FirstCovered(t *testing.T) {
+	set := IntervalSet{{5, 8}, {12, 12}}
+	tests := []struct {
+		from, want int
+		ok         bool
+	}{
+		{0, 5, true}, {6, 6, true}, {8, 0, false}, {20, 0, false},
+	}
+	for _, tt := range tests {
+		if got, ok := set.FirstCovered(tt.from); got != tt.want || ok != tt.ok {
+			t.Errorf("FirstCovered(%d) = %d, %v, want %d, %v", tt.from, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestIntervalSet_Boundaries(t *testing.T) {
+	if got, want := (IntervalSet{{2, 5}, {0, 3}, {3, 5}}).Boundaries(), []int{0, 2, 3, 5}; !slices.Equal(got, want) {
+		t.Errorf("Boundaries() = %v, want %v", got, want)
+	}
+	if got := IntervalSet(nil).Boundaries(); got != nil {
+		t.Errorf("Boundaries() of empty set = %v, want nil", got)
+	}
+}
+
+func TestIntervalSet_BestWindow(t *testing.T) {
+	set := IntervalSet{{0, 1}, {10, 12}, {13, 15}, {30, 31}}
+	if got, covered := set.BestWindow(5, IntegerInterval{0, 20}); got != (IntegerInterval{10, 15}) || covered != 4 {
+		t.Errorf("BestWindow(5) = %v, %d, want [10,15), 4", got, covered)
+	}
+	if got, covered := set.BestWindow(3, IntegerInterval{0, 20}); got != (IntegerInterval{9, 12}) || covered != 2 {
+		t.Errorf("BestWindow(3) = %v, %d, want [9,12), 2", got, covered)
+	}
+	// 窓は base の中に収まる
+	if got, covered := (IntervalSet{{0, 10}}).BestWindow(4, IntegerInterval{7, 20}); got != (IntegerInterval{7, 11}) || covered != 3 {
+		t.Errorf("BestWindow clipped = %v, %d, want [7,11), 3", got, covered)
+	}
+	if _, covered := set.BestWindow(30, IntegerInterval{0, 20}); covered != 0 {
+		t.Errorf("BestWindow wider than base covered %d", covered)
+	}
+
+	// 全ての位置を試す素朴な実装と一致すること
+	r := rand.New(rand.NewPCG(17, 18))
+	for range 200 {
+		set, w, base := randomSet(r), 1+r.IntN(10), IntegerInterval{0, 50}
+		_, covered := set.BestWindow(w, base)
+		want := 0
+		for s := base.Start; s+w <= base.End; s++ {
+			want = max(want, set.IntersectLength(IntervalSet{{s, s + w}}))
+		}
+		if covered != want {
+			t.Fatalf("%v.BestWindow(%d) covered %d, want %d", set, w, covered, want)
+		}
+	}
+}
+
+func TestIntervalSet_SnapToCovered(t *testing.T) {
+	set := IntervalSet{{6, 12}, {0, 2}, {2, 4}}
+	tests := []struct {
+		iv   IntegerInterval
+		want IntegerInterval
+		ok   bool
+	}{
+		{IntegerInterval{3, 8}, IntegerInterval{6, 12}, true},
+		{IntegerInterval{2, 7}, IntegerInterval{0, 4}, true},
+		{IntegerInterval{3, 7}, IntegerInterval{0, 4}, true}, // 同点なら先の領域
+		{IntegerInterval{4, 6}, IntegerInterval{}, false},
+	}
+	for _, tt := range tests {
+		if got, ok := set.SnapToCovered(tt.iv); got != tt.want || ok != tt.ok {
+			t.Errorf("SnapToCovered(%v) = %v, %v, want %v, %v", tt.iv, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestIntervalSet_RegionCount(t *testing.T) {
+	tests := []struct {
+		set  IntervalSet
+		want int
+	}{
+		{IntervalSet{{0, 2}, {2, 4}, {10, 12}}, 2},
+		{IntervalSet{{0, 5}, {1, 3}, {4, 8}}, 1},
+		{IntervalSet{{0, 1}, {2, 3}, {4, 5}}, 3},
+		{IntervalSet{{6, 6}}, 0},
+		{nil, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.set.RegionCount(); got != tt.want {
+			t.Errorf("%v.RegionCount() = %d, want %d", tt.set, got, tt.want)
+		}
+	}
+}
+
+func TestIntervalSet_NthRegion(t *testing.T) {
+	set := IntervalSet{{5, 8}, {0, 2}, {1, 3}, {10, 10}, {8, 9}}
+	tests := []struct {
+		n    int
+		want IntegerInterval
+		ok   bool
+	}{
+		{0, IntegerInterval{0, 3}, true},
+		{1, IntegerInterval{5, 9}, true},
+		{2, IntegerInterval{}, false},
+		{-1, IntegerInterval{}, false},
+	}
+	for _, tt := range tests {
+		if got, ok := set.NthRegion(tt.n); got != tt.want || ok != tt.ok {
+			t.Errorf("NthRegion(%d) = %v, %v, want %v, %v", tt.n, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestIntervalSet_IntervalsCovering(t *testing.T) {
+	set := IntervalSet{{2, 8}, {0, 5}, {6, 9}}
+	if got, want := set.IntervalsCovering(3), (IntervalSet{{2, 8}, {0, 5}}); !slices.Equal(got, want) {
+		t.Errorf("IntervalsCovering(3) = %v, want %v", got, want)
+	}
+	if got := set.IntervalsCovering(9); len(got) != 0 {
+		t.Errorf("IntervalsCovering(9) = %v, want {}", got)
+	}
+}
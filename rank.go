@@ -0,0 +1,179 @@
+package interval
+
+import (
+	"math/rand/v2"
+	"sort"
+)
+
+// NthCovered returns the k-th (0-based) covered integer of the set,
+// treating the normalized covered positions as one flat sequence.
+//
+// Returns false if k is negative or not less than TotalLength().
+//
+// For example:
+//
+//	set = {[0,2), [5,7)}
+//	k = 0 → 0, k = 1 → 1, k = 2 → 5, k = 3 → 6, k = 4 → false
+func (set IntervalSet) NthCovered(k int) (pos int, ok bool) {
+	if k < 0 {
+		return 0, false
+	}
+	for _, iv := range set.Normalize() {
+		if k < iv.Length() {
+			return iv.Start + k, true
+		}
+		k -= iv.Length()
+	}
+	return 0, false
+}
+
+// CoveredRank returns the number of covered integers of the set that precede pos,
+// i.e. the index of pos in the flat covered sequence. It is the inverse of NthCovered.
+//
+// Returns false if pos is not covered.
+//
+// For example:
+//
+//	set = {[0,2), [5,7)}
+//	pos = 5 → 2, pos = 3 → false
+//
+// NthCovered(CoveredRank(pos)) = pos
+func (set IntervalSet) CoveredRank(pos int) (rank int, ok bool) {
+	for _, iv := range set.Normalize() {
+		if iv.Contains(pos) {
+			return rank + pos - iv.Start, true
+		}
+		if pos < iv.Start {
+			break
+		}
+		rank += iv.Length()
+	}
+	return 0, false
+}
+
+// CoveredPercentile returns the covered integer at fraction p ∈ [0,1] of the flat covered sequence.
+//
+// The index is ⌊p · (TotalLength() − 1)⌋, so p = 0 gives the first covered integer,
+// p = 1 the last, and p = 0.5 the (lower) median.
+// Returns false if the set is empty or p is outside [0,1].
+//
+// For example:
+//
+//	set = {[0,2), [5,7)}
+//	p = 0 → 0, p = 0.5 → 1, p = 1 → 6
+//
+// CoveredPercentile(p) = NthCovered(⌊p · (TotalLength() − 1)⌋)
+func (set IntervalSet) CoveredPercentile(p float64) (pos int, ok bool) {
+	total := set.TotalLength()
+	if total == 0 || !(p >= 0 && p <= 1) {
+		return 0, false
+	}
+	return set.NthCovered(int(p * float64(total-1)))
+}
+
+// Centroid returns the mean of all covered integers, so each region pulls in proportion to its length.
+//
+// Overlapping members are counted once. Returns false if the set covers nothing.
+//
+// For example:
+//
+//	set    = {[0,2), [10,12)}
+//	result = 5.5 (the mean of 0, 1, 10, 11)
+//
+// Centroid() = Σ(x ∈ set) x / |set|
+func (set IntervalSet) Centroid() (float64, bool) {
+	total, sum := 0, 0.0
+	for _, iv := range set.canonical() {
+		total += iv.Length()
+		// [s, e) の整数の和は |iv| · (s + e − 1) / 2
+		sum += float64(iv.Length()) * float64(iv.Start+iv.End-1) / 2
+	}
+	if total == 0 {
+		return 0, false
+	}
+	return sum / float64(total), true
+}
+
+// SamplePoints draws n covered integers of the set uniformly at random, with replacement,
+// using rng as the source of randomness.
+//
+// Each draw picks a uniform index into the flat covered sequence (see NthCovered),
+// so every covered integer is equally likely and a region is drawn in proportion to its length.
+// Returns an empty slice if n ≤ 0 or the set covers nothing.
+func (set IntervalSet) SamplePoints(n int, rng *rand.Rand) []int {
+	normalized := set.canonical()
+	prefix := make([]int, len(normalized)+1) // prefix[i] は normalized[:i] の被覆長
+	for i, iv := range normalized {
+		prefix[i+1] = prefix[i] + iv.Length()
+	}
+	total := prefix[len(normalized)]
+	if n <= 0 || total == 0 {
+		return []int{}
+	}
+	points := make([]int, n)
+	for i := range points {
+		k := rng.IntN(total)
+		j := sort.Search(len(normalized), func(j int) bool { return prefix[j+1] > k })
+		points[i] = normalized[j].Start + k - prefix[j]
+	}
+	return points
+}
+
+// CoveredRange returns the covered positions with ranks k, …, m−1 in the flat covered sequence
+// (see NthCovered) as a normalized set, which has several members if the range spans a gap.
+//
+// Returns false unless 0 ≤ k ≤ m ≤ TotalLength(). k = m gives an empty set.
+//
+// For example:
+//
+//	set = {[0,2), [5,8)}
+//	k = 1, m = 4 → {[1,2), [5,7)}
+func (set IntervalSet) CoveredRange(k, m int) (IntervalSet, bool) {
+	normalized := set.canonical()
+	if k < 0 || m < k || m > normalized.TotalLength() {
+		return nil, false
+	}
+	return coveredSlice(normalized, k, m), true
+}
+
+// SplitByCoverage cuts the normalized set into n consecutive parts of near-equal covered length,
+// splitting intervals where needed, and returns them from left to right.
+//
+// Part i holds the covered positions with ranks (see CoveredRank) in [i·T/n, (i+1)·T/n),
+// where T = TotalLength(), so part lengths differ by at most one and together they partition
+// the covered region exactly. If T < n, some parts are empty (nil). Returns nil if n ≤ 0.
+//
+// For example:
+//
+//	set = {[0,4), [10,12)}, n = 2
+//	result = {[0,3)}, {[3,4), [10,12)}
+func (set IntervalSet) SplitByCoverage(n int) []IntervalSet {
+	if n <= 0 {
+		return nil
+	}
+	normalized := set.canonical()
+	total := normalized.TotalLength()
+	parts := make([]IntervalSet, n)
+	for i := range parts {
+		parts[i] = coveredSlice(normalized, i*total/n, (i+1)*total/n)
+	}
+	return parts
+}
+
+// coveredSlice は正規化済み集合の被覆位置のうち、順位が [lo, hi) のものを区間集合として返す。
+func coveredSlice(normalized IntervalSet, lo, hi int) IntervalSet {
+	var result IntervalSet
+	rank := 0 // iv.Start の順位
+	for _, iv := range normalized {
+		if rank >= hi {
+			break
+		}
+		start := iv.Start + max(lo-rank, 0)
+		end := iv.Start + min(hi-rank, iv.Length())
+		if start < end {
+			result = append(result, IntegerInterval{Start: start, End: end})
+		}
+		rank += iv.Length()
+	}
+	return result
+}
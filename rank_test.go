@@ -0,0 +1,167 @@
+package interval
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestIntervalSet_NthCovered(t *testing.T) {
+	set := IntervalSet{{5, 7}, {0, 2}}
+	tests := []struct {
+		k, want int
+		ok      bool
+	}{
+		{0, 0, true}, {1, 1, true}, {2, 5, true}, {3, 6, true}, {4, 0, false}, {-1, 0, false},
+	}
+	for _, tt := range tests {
+		if got, ok := set.NthCovered(tt.k); got != tt.want || ok != tt.ok {
+			t.Errorf("NthCovered(%d) = %d, %v, want %d, %v", tt.k, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestIntervalSet_CoveredRank(t *testing.T) {
+	set := IntervalSet{{5, 7}, {0, 2}}
+	tests := []struct {
+		pos, want int
+		ok        bool
+	}{
+		{0, 0, true}, {1, 1, true}, {5, 2, true}, {6, 3, true}, {3, 0, false}, {7, 0, false},
+	}
+	for _, tt := range tests {
+		if got, ok := set.CoveredRank(tt.pos); got != tt.want || ok != tt.ok {
+			t.Errorf("CoveredRank(%d) = %d, %v, want %d, %v", tt.pos, got, ok, tt.want, tt.ok)
+		}
+	}
+	for k := range set.TotalLength() {
+		pos, _ := set.NthCovered(k)
+		if rank, _ := set.CoveredRank(pos); rank != k {
+			t.Errorf("CoveredRank(NthCovered(%d)) = %d", k, rank)
+		}
+	}
+}
+
+func TestIntervalSet_CoveredPercentile(t *testing.T) {
+	set := IntervalSet{{5, 8}, {0, 2}}
+	tests := []struct {
+		p    float64
+		want int
+		ok   bool
+	}{
+		{0, 0, true}, {0.5, 5, true}, {1, 7, true}, {-0.1, 0, false}, {1.5, 0, false},
+	}
+	for _, tt := range tests {
+		if got, ok := set.CoveredPercentile(tt.p); got != tt.want || ok != tt.ok {
+			t.Errorf("CoveredPercentile(%v) = %d, %v, want %d, %v", tt.p, got, ok, tt.want, tt.ok)
+		}
+	}
+	if _, ok := IntervalSet(nil).CoveredPercentile(0.5); ok {
+		t.Error("CoveredPercentile on empty set reported ok")
+	}
+}
+
+func TestIntervalSet_Centroid(t *testing.T) {
+	tests := []struct {
+		set  IntervalSet
+		want float64
+		ok   bool
+	}{
+		{IntervalSet{{4, 9}}, 6, true},
+		{IntervalSet{{0, 2}, {10, 12}}, 5.5, true},
+		{IntervalSet{{10, 12}, {0, 2}, {1, 2}}, 5.5, true}, // 重複は一度だけ数える
+		{IntervalSet{{0, 1}, {9, 12}}, 7.5, true},
+		{IntervalSet{{3, 3}}, 0, false},
+		{nil, 0, false},
+	}
+	for _, tt := range tests {
+		if got, ok := tt.set.Centroid(); got != tt.want || ok != tt.ok {
+			t.Errorf("%v.Centroid() = %v, %v, want %v, %v", tt.set, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestIntervalSet_SamplePoints(t *testing.T) {
+	set := IntervalSet{{0, 10}, {100, 130}}
+	r := rand.New(rand.NewPCG(33, 34))
+	points := set.SamplePoints(4000, r)
+	if len(points) != 4000 {
+		t.Fatalf("got %d points, want 4000", len(points))
+	}
+	inFirst := 0
+	for _, p := range points {
+		if !set.ContainsPoint(p) {
+			t.Fatalf("sampled uncovered point %d", p)
+		}
+		if p < 10 {
+			inFirst++
+		}
+	}
+	// [0,10) は被覆長の 1/4 なので約 1000 回選ばれる
+	if inFirst < 850 || inFirst > 1150 {
+		t.Errorf("%d of 4000 points in [0,10), want about 1000", inFirst)
+	}
+	if got := IntervalSet(nil).SamplePoints(5, r); got == nil || len(got) != 0 {
+		t.Errorf("SamplePoints of empty set = %#v, want empty slice", got)
+	}
+}
+
+func TestIntervalSet_CoveredRange(t *testing.T) {
+	set := IntervalSet{{5, 8}, {0, 2}}
+	tests := []struct {
+		k, m int
+		want IntervalSet
+		ok   bool
+	}{
+		{1, 4, IntervalSet{{1, 2}, {5, 7}}, true},
+		{0, 5, IntervalSet{{0, 2}, {5, 8}}, true},
+		{2, 3, IntervalSet{{5, 6}}, true},
+		{3, 3, nil, true},
+		{4, 6, nil, false},
+		{-1, 2, nil, false},
+		{3, 2, nil, false},
+	}
+	for _, tt := range tests {
+		got, ok := set.CoveredRange(tt.k, tt.m)
+		if !slices.Equal(got, tt.want) || ok != tt.ok {
+			t.Errorf("CoveredRange(%d, %d) = %v, %v, want %v, %v", tt.k, tt.m, got, ok, tt.want, tt.ok)
+		}
+		if ok && tt.m > tt.k {
+			first, _ := set.NthCovered(tt.k)
+			last, _ := set.NthCovered(tt.m - 1)
+			if got[0].Start != first || got[len(got)-1].End != last+1 {
+				t.Errorf("CoveredRange(%d, %d) = %v disagrees with NthCovered", tt.k, tt.m, got)
+			}
+		}
+	}
+}
+
+func TestIntervalSet_SplitByCoverage(t *testing.T) {
+	set := IntervalSet{{10, 12}, {0, 4}, {3, 5}, {20, 27}}
+	parts := set.SplitByCoverage(3)
+	want := []IntervalSet{{{0, 4}}, {{4, 5}, {10, 12}, {20, 22}}, {{22, 27}}}
+	if !slices.EqualFunc(parts, want, slices.Equal) {
+		t.Errorf("SplitByCoverage(3) = %v, want %v", parts, want)
+	}
+
+	r := rand.New(rand.NewPCG(41, 42))
+	for range 200 {
+		set, n := randomSet(r), 1+r.IntN(5)
+		parts := set.SplitByCoverage(n)
+		var all IntervalSet
+		lo, hi := set.TotalLength(), 0
+		for _, part := range parts {
+			all = append(all, part...)
+			lo, hi = min(lo, part.TotalLength()), max(hi, part.TotalLength())
+		}
+		if len(parts) != n || all.DepthIntegral() != set.TotalLength() || !slices.Equal(all.canonical(), set.canonical()) {
+			t.Fatalf("%v.SplitByCoverage(%d) = %v does not partition the set", set, n, parts)
+		}
+		if hi-lo > 1 {
+			t.Fatalf("%v.SplitByCoverage(%d) = %v has unbalanced parts", set, n, parts)
+		}
+	}
+	if got := set.SplitByCoverage(0); got != nil {
+		t.Errorf("SplitByCoverage(0) = %v, want nil", got)
+	}
+}
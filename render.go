@@ -0,0 +1,26 @@
+package interval
+
+// Rect is an axis-aligned rectangle for drawing intervals: X and W come from the interval,
+// Y and H are chosen by the caller.
+type Rect struct {
+	X, Y, W, H int
+}
+
+// Segments2D maps each merged (normalized) interval of the set to a rectangle
+// {X: Start, Y: y, W: Length(), H: height}, ready for a drawing layer.
+//
+// Empty members are dropped, so every rectangle has a positive width.
+// Returns an empty, non-nil slice for an empty set.
+//
+// For example:
+//
+//	set = {[0,2), [1,4), [6,7)}, y = 10, height = 3
+//	result = {0,10,4,3}, {6,10,1,3}
+func (set IntervalSet) Segments2D(y, height int) []Rect {
+	canonical := set.canonical()
+	result := make([]Rect, 0, len(canonical))
+	for _, iv := range canonical {
+		result = append(result, Rect{X: iv.Start, Y: y, W: iv.Length(), H: height})
+	}
+	return result
+}
@@ -0,0 +1,26 @@
+package interval
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestIntervalSet_Segments2D(t *testing.T) {
+	set := IntervalSet{{6, 7}, {0, 2}, {1, 4}}
+	want := []Rect{{0, 10, 4, 3}, {6, 10, 1, 3}}
+	got := set.Segments2D(10, 3)
+	if !slices.Equal(got, want) {
+		t.Errorf("Segments2D = %v, want %v", got, want)
+	}
+	for i, iv := range set.Normalize() {
+		if got[i].W != iv.Length() {
+			t.Errorf("width %d = %d, want %d", i, got[i].W, iv.Length())
+		}
+	}
+	if got := (IntervalSet{{5, 5}, {10, 12}}).Segments2D(0, 1); !slices.Equal(got, []Rect{{10, 0, 2, 1}}) {
+		t.Errorf("Segments2D with an empty member = %v, want only {10,0,2,1}", got)
+	}
+	if got := IntervalSet(nil).Segments2D(0, 1); got == nil || len(got) != 0 {
+		t.Errorf("Segments2D of empty set = %#v, want empty slice", got)
+	}
+}
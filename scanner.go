@@ -0,0 +1,28 @@
+package interval
+
+// CoverageScanner answers "is pos covered" for a single forward scan over positions,
+// in O(1) amortized time per query.
+//
+// It keeps an index into the normalized set and only ever advances it, instead of
+// binary-searching on every query. Positions passed to Covered must be non-decreasing;
+// querying a position smaller than the previous one gives an undefined result.
+// Build a new scanner to scan again.
+type CoverageScanner struct {
+	set IntervalSet
+	i   int
+}
+
+// NewCoverageScanner returns a CoverageScanner over the region covered by set.
+func NewCoverageScanner(set IntervalSet) *CoverageScanner {
+	return &CoverageScanner{set: set.canonical()}
+}
+
+// Covered reports whether pos is covered by the set.
+//
+// pos must not be smaller than the pos of the previous call.
+func (cs *CoverageScanner) Covered(pos int) bool {
+	for cs.i < len(cs.set) && cs.set[cs.i].End <= pos {
+		cs.i++
+	}
+	return cs.i < len(cs.set) && cs.set[cs.i].Start <= pos
+}
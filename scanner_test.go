@@ -0,0 +1,28 @@
+package interval
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+func TestCoverageScanner_Covered(t *testing.T) {
+	set := IntervalSet{{5, 8}, {0, 2}, {2, 3}, {10, 11}}
+	cs := NewCoverageScanner(set)
+	for pos := -2; pos < 14; pos++ {
+		if got, want := cs.Covered(pos), set.ContainsPoint(pos); got != want {
+			t.Errorf("Covered(%d) = %v, want %v", pos, got, want)
+		}
+	}
+
+	// 同じ位置の繰り返しや飛び飛びの位置も単調なら正しい
+	r := rand.New(rand.NewPCG(27, 28))
+	for range 100 {
+		set := randomSet(r)
+		cs := NewCoverageScanner(set)
+		for pos := -1; pos < 55; pos += r.IntN(4) {
+			if got, want := cs.Covered(pos), set.ContainsPoint(pos); got != want {
+				t.Fatalf("%v: Covered(%d) = %v, want %v", set, pos, got, want)
+			}
+		}
+	}
+}
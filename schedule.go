@@ -0,0 +1,187 @@
+package interval
+
+import (
+	"cmp"
+	"slices"
+	"sort"
+)
+
+// CoverPoints returns the fewest intervals of length ≤ maxLen that together contain every point.
+//
+// Points are sorted and covered greedily: an interval starts at the first uncovered point
+// and takes every point within maxLen of it. Every interval but the last spans the full maxLen;
+// the last one ends right after the last point, so the result does not reach past the points.
+// Returns nil if there are no points or maxLen ≤ 0.
+//
+// For example:
+//
+//	points = [0, 1, 5], maxLen = 3
+//	result = {[0,3), [5,6)}
+func CoverPoints(points []int, maxLen int) IntervalSet {
+	if len(points) == 0 || maxLen <= 0 {
+		return nil
+	}
+	sorted := slices.Clone(points)
+	slices.Sort(sorted)
+
+	var result IntervalSet
+	start := sorted[0]
+	for _, p := range sorted[1:] {
+		if p >= start+maxLen {
+			result = append(result, IntegerInterval{Start: start, End: start + maxLen})
+			start = p
+		}
+	}
+	return append(result, IntegerInterval{Start: start, End: sorted[len(sorted)-1] + 1})
+}
+
+// MaxDisjointSubset returns a largest subset of mutually non-overlapping members of the set
+// (activity selection), sorted by position.
+//
+// The classic greedy is used: sort by End and take every interval that starts at or after
+// the End of the last one taken. Adjacent intervals do not conflict.
+// The number of intervals to drop to make the set disjoint is len(set) − len(result).
+//
+// For example:
+//
+//	set    = {[0,4), [3,6), [5,8), [7,10)}
+//	result = {[0,4), [5,8)}
+func (set IntervalSet) MaxDisjointSubset() IntervalSet {
+	sorted := slices.Clone(set)
+	slices.SortFunc(sorted, func(a, b IntegerInterval) int {
+		return cmp.Or(cmp.Compare(a.End, b.End), cmp.Compare(a.Start, b.Start))
+	})
+	var result IntervalSet
+	for _, iv := range sorted {
+		if len(result) == 0 || iv.Start >= result[len(result)-1].End {
+			result = append(result, iv)
+		}
+	}
+	return result
+}
+
+// StabbingPoints returns the fewest points such that every non-empty member of the set
+// contains at least one of them, in increasing order.
+//
+// The greedy is used: sort by End and, for each member not yet stabbed, choose its last
+// integer End − 1, which stabs as many later members as possible. Empty members contain
+// no point and are ignored.
+//
+// For example:
+//
+//	set    = {[0,3), [2,5), [6,8)}
+//	result = [2, 7]
+func (set IntervalSet) StabbingPoints() []int {
+	var points []int
+	for _, iv := range sortedByEnd(set) {
+		if iv.Length() <= 0 {
+			continue
+		}
+		if n := len(points); n == 0 || points[n-1] < iv.Start {
+			points = append(points, iv.End-1)
+		}
+	}
+	return points
+}
+
+// LongestChain returns a longest chain of the set's members in which each one starts
+// at or after the End of the previous one, sorted by position. Adjacent intervals chain.
+//
+// The chain is found by dynamic programming over the members sorted by End, with a binary
+// search for each member's latest compatible predecessor, in O(n log n).
+//
+// For example:
+//
+//	set    = {[0,10), [1,3), [3,5), [5,7), [8,9)}
+//	result = {[1,3), [3,5), [5,7), [8,9)}
+func (set IntervalSet) LongestChain() IntervalSet {
+	sorted := sortedByEnd(set)
+	var result IntervalSet
+	for _, i := range maxWeightChain(sorted, func(int) int { return 1 }) {
+		result = append(result, sorted[i])
+	}
+	return result
+}
+
+// sortedByEnd は End（同じなら Start）の昇順に並べた set のコピーを返す。
+func sortedByEnd(set IntervalSet) IntervalSet {
+	sorted := slices.Clone(set)
+	slices.SortFunc(sorted, func(a, b IntegerInterval) int {
+		return cmp.Or(cmp.Compare(a.End, b.End), cmp.Compare(a.Start, b.Start))
+	})
+	return sorted
+}
+
+// maxWeightChain は End でソート済みの sorted から、互いに重ならず weight の和が最大となる
+// 部分列の添字を昇順で返す。best[i] は sorted[:i] だけを使ったときの最大値。
+func maxWeightChain(sorted IntervalSet, weight func(i int) int) []int {
+	best := make([]int, len(sorted)+1)
+	pred := make([]int, len(sorted))
+	for i, iv := range sorted {
+		// sorted[:pred[i]] は iv の Start までに終わる
+		pred[i] = sort.Search(i, func(j int) bool { return sorted[j].End > iv.Start })
+		best[i+1] = max(best[i], best[pred[i]]+weight(i))
+	}
+	var chain []int
+	for i := len(sorted); i > 0; {
+		if best[i] == best[i-1] {
+			i--
+			continue
+		}
+		chain = append(chain, i-1)
+		i = pred[i-1]
+	}
+	slices.Reverse(chain)
+	return chain
+}
+
+// WeightedInterval is an interval carrying an integer weight (a priority, a profit, a size, ...).
+type WeightedInterval struct {
+	IntegerInterval
+	Weight int
+}
+
+// WeightedSet is a set of weighted intervals. Like IntervalSet it may be unsorted and overlapping.
+type WeightedSet []WeightedInterval
+
+// WeightedIntegral returns the sum of weight × length over the members, i.e. the coverage
+// depth weighted by each member's weight and summed over all positions. Overlaps count once
+// per member, and empty or invalid members count 0.
+//
+// For example:
+//
+//	ws     = {[0,4) 2, [2,5) 10}
+//	result = 38
+func (ws WeightedSet) WeightedIntegral() int {
+	total := 0
+	for _, w := range ws {
+		total += w.Weight * max(w.Length(), 0)
+	}
+	return total
+}
+
+// MaxWeightSchedule returns a subset of mutually non-overlapping members with the largest
+// total weight (weighted interval scheduling), sorted by position. Adjacent intervals do not conflict.
+//
+// The same O(n log n) dynamic program as LongestChain is used, with each member's weight
+// in place of 1. Members with weight ≤ 0 are never chosen.
+//
+// For example:
+//
+//	ws     = {[0,4) 5, [3,6) 8, [5,8) 4, [6,9) 3}
+//	result = {[3,6) 8, [6,9) 3}
+func (ws WeightedSet) MaxWeightSchedule() WeightedSet {
+	sorted := slices.Clone(ws)
+	slices.SortFunc(sorted, func(a, b WeightedInterval) int {
+		return cmp.Or(cmp.Compare(a.End, b.End), cmp.Compare(a.Start, b.Start))
+	})
+	intervals := make(IntervalSet, len(sorted))
+	for i, w := range sorted {
+		intervals[i] = w.IntegerInterval
+	}
+	var result WeightedSet
+	for _, i := range maxWeightChain(intervals, func(i int) int { return sorted[i].Weight }) {
+		result = append(result, sorted[i])
+	}
+	return result
+}
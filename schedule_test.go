@@ -0,0 +1,150 @@
+package interval
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestCoverPoints(t *testing.T) {
+	tests := []struct {
+		points []int
+		maxLen int
+		want   IntervalSet
+	}{
+		{[]int{0, 1, 5}, 3, IntervalSet{{0, 3}, {5, 6}}},
+		{[]int{4, 0, 2, 1, 3, 2}, 3, IntervalSet{{0, 3}, {3, 5}}},
+		{[]int{0, 10, 20}, 5, IntervalSet{{0, 5}, {10, 15}, {20, 21}}}, // 最後の区間だけ点の直後で閉じる
+		{[]int{7, 8}, 4, IntervalSet{{7, 9}}},
+		{[]int{0, 1, 2}, 1, IntervalSet{{0, 1}, {1, 2}, {2, 3}}},
+		{nil, 3, nil},
+		{[]int{1}, 0, nil},
+	}
+	for _, tt := range tests {
+		got := CoverPoints(tt.points, tt.maxLen)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("CoverPoints(%v, %d) = %v, want %v", tt.points, tt.maxLen, got, tt.want)
+		}
+		for _, iv := range got {
+			if iv.Length() > tt.maxLen {
+				t.Errorf("CoverPoints(%v, %d) produced %v longer than maxLen", tt.points, tt.maxLen, iv)
+			}
+		}
+	}
+}
+
+func TestIntervalSet_MaxDisjointSubset(t *testing.T) {
+	tests := []struct {
+		set, want IntervalSet
+	}{
+		{IntervalSet{{0, 4}, {3, 6}, {5, 8}, {7, 10}}, IntervalSet{{0, 4}, {5, 8}}},
+		// 最初に始まる長い区間を選ぶと 1 つしか取れない
+		{IntervalSet{{0, 10}, {1, 3}, {3, 5}, {5, 7}, {8, 9}}, IntervalSet{{1, 3}, {3, 5}, {5, 7}, {8, 9}}},
+		{nil, nil},
+	}
+	for _, tt := range tests {
+		if got := tt.set.MaxDisjointSubset(); !slices.Equal(got, tt.want) {
+			t.Errorf("%v.MaxDisjointSubset() = %v, want %v", tt.set, got, tt.want)
+		}
+	}
+}
+
+func TestIntervalSet_StabbingPoints(t *testing.T) {
+	tests := []struct {
+		set  IntervalSet
+		want []int
+	}{
+		{IntervalSet{{0, 3}, {2, 5}, {6, 8}}, []int{2, 7}},
+		// 共通点を持つ区間の塊は 1 点で刺せる
+		{IntervalSet{{0, 10}, {4, 6}, {5, 9}, {3, 7}}, []int{5}},
+		{IntervalSet{{6, 7}, {0, 1}, {2, 3}}, []int{0, 2, 6}},
+		{IntervalSet{{0, 2}, {2, 4}}, []int{1, 3}},
+		{IntervalSet{{3, 3}}, nil},
+		{nil, nil},
+	}
+	for _, tt := range tests {
+		got := tt.set.StabbingPoints()
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%v.StabbingPoints() = %v, want %v", tt.set, got, tt.want)
+		}
+	}
+}
+
+func TestIntervalSet_LongestChain(t *testing.T) {
+	tests := []struct {
+		set, want IntervalSet
+	}{
+		// 最も早く始まる区間から貪欲に取ると [0,10), [10,11) の 2 つで終わる
+		{IntervalSet{{0, 10}, {1, 3}, {3, 5}, {5, 7}, {8, 9}, {10, 11}}, IntervalSet{{1, 3}, {3, 5}, {5, 7}, {8, 9}, {10, 11}}},
+		{IntervalSet{{0, 4}, {3, 6}, {5, 8}, {7, 10}}, IntervalSet{{0, 4}, {5, 8}}},
+		{IntervalSet{{2, 5}}, IntervalSet{{2, 5}}},
+		{nil, nil},
+	}
+	for _, tt := range tests {
+		got := tt.set.LongestChain()
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%v.LongestChain() = %v, want %v", tt.set, got, tt.want)
+		}
+		if len(got) != len(tt.set.MaxDisjointSubset()) {
+			t.Errorf("%v.LongestChain() has %d members, MaxDisjointSubset has %d", tt.set, len(got), len(tt.set.MaxDisjointSubset()))
+		}
+	}
+}
+
+func TestWeightedSet_WeightedIntegral(t *testing.T) {
+	ws := WeightedSet{{IntegerInterval{0, 4}, 2}, {IntegerInterval{2, 5}, 10}, {IntegerInterval{6, 6}, 100}}
+	if got := ws.WeightedIntegral(); got != 2*4+10*3 {
+		t.Errorf("WeightedIntegral() = %d, want %d", got, 2*4+10*3)
+	}
+	if got := (WeightedSet(nil)).WeightedIntegral(); got != 0 {
+		t.Errorf("WeightedIntegral() of empty set = %d", got)
+	}
+}
+
+func TestWeightedSet_MaxWeightSchedule(t *testing.T) {
+	ws := WeightedSet{{IntegerInterval{0, 4}, 5}, {IntegerInterval{3, 6}, 8}, {IntegerInterval{5, 8}, 4}, {IntegerInterval{6, 9}, 3}}
+	want := WeightedSet{{IntegerInterval{3, 6}, 8}, {IntegerInterval{6, 9}, 3}}
+	if got := ws.MaxWeightSchedule(); !slices.Equal(got, want) {
+		t.Errorf("MaxWeightSchedule() = %v, want %v", got, want)
+	}
+
+	r := rand.New(rand.NewPCG(21, 22))
+	totalWeight := func(ws WeightedSet) int {
+		total := 0
+		for _, w := range ws {
+			total += w.Weight
+		}
+		return total
+	}
+	for range 300 {
+		ws := make(WeightedSet, r.IntN(8))
+		for i := range ws {
+			start := r.IntN(20)
+			ws[i] = WeightedInterval{IntegerInterval{start, start + 1 + r.IntN(6)}, r.IntN(10) + 1}
+		}
+		got := ws.MaxWeightSchedule()
+		for i := 1; i < len(got); i++ {
+			if got[i].Start < got[i-1].End {
+				t.Fatalf("%v.MaxWeightSchedule() = %v has overlapping members", ws, got)
+			}
+		}
+		// 全部分集合を試した最適値と比べる
+		best := 0
+		for mask := range 1 << len(ws) {
+			var chosen IntervalSet
+			weight := 0
+			for i, w := range ws {
+				if mask&(1<<i) != 0 {
+					chosen = append(chosen, w.IntegerInterval)
+					weight += w.Weight
+				}
+			}
+			if len(chosen.SelfIntersections()) == 0 {
+				best = max(best, weight)
+			}
+		}
+		if totalWeight(got) != best {
+			t.Fatalf("%v.MaxWeightSchedule() weighs %d, want %d", ws, totalWeight(got), best)
+		}
+	}
+}
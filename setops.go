@@ -0,0 +1,276 @@
+package interval
+
+import "slices"
+
+// ComplementIn returns the part of universe not covered by the set.
+//
+// It generalizes Complement to a domain made of several intervals.
+// The result is normalized.
+//
+// For example:
+//
+//	universe = {[0,5), [10,15)}
+//	set      = {[2,12)}
+//	result   = {[0,2), [12,15)}
+//
+// ComplementIn(U) = ⋃U − ⋃(iv ∈ set)
+func (set IntervalSet) ComplementIn(universe IntervalSet) IntervalSet {
+	return sweepSet(universe, set, func(inU, inSet bool) bool {
+		return inU && !inSet
+	})
+}
+
+// ComplementWithin is the same as ComplementIn: it returns bases − set,
+// treating the normalized bases as the domain. Parts of the set outside bases are ignored.
+//
+// For example:
+//
+//	bases  = {[0,5), [10,15)}
+//	set    = {[2,12)}
+//	result = {[0,2), [12,15)}
+func (set IntervalSet) ComplementWithin(bases IntervalSet) IntervalSet {
+	return set.ComplementIn(bases)
+}
+
+// SubtractSet returns the part of the set not covered by other.
+//
+// The result is normalized.
+//
+// For example:
+//
+//	a = {[0,5), [8,10)}
+//	b = {[3,9)}
+//	result = {[0,3), [9,10)}
+//
+// SubtractSet(set') = set − set'
+func (set IntervalSet) SubtractSet(other IntervalSet) IntervalSet {
+	return other.ComplementIn(set)
+}
+
+// SymmetricDifference returns the integers covered by exactly one of the set and other.
+//
+// The result is normalized.
+//
+// For example:
+//
+//	a = {[0,3)}
+//	b = {[2,5)}
+//	result = {[0,2), [3,5)}
+//
+// SymmetricDifference(set') = set △ set' = (set − set') ∪ (set' − set)
+func (set IntervalSet) SymmetricDifference(other IntervalSet) IntervalSet {
+	return sweepSet(set, other, func(inA, inB bool) bool {
+		return inA != inB
+	})
+}
+
+// FirstDifference returns the smallest position covered by exactly one of the set and other,
+// scanning their endpoints only up to the first mismatch instead of building the symmetric difference.
+//
+// Returns false if both cover exactly the same integers.
+//
+// For example:
+//
+//	a = {[0,5), [8,10)}
+//	b = {[0,3), [3,5), [8,11)}
+//	result = 10
+//
+// FirstDifference(set') = min(set △ set')
+func (set IntervalSet) FirstDifference(other IntervalSet) (pos int, ok bool) {
+	events := sweepEndpoints(set, other)
+	var depth [2]int
+	for i, e := range events {
+		depth[e.side] += e.delta
+		if i+1 < len(events) && events[i+1].pos > e.pos && (depth[0] > 0) != (depth[1] > 0) {
+			return e.pos, true
+		}
+	}
+	return 0, false
+}
+
+// Toggle flips the coverage of every integer in iv: uncovered parts of iv are added
+// and covered parts removed. The result is normalized.
+//
+// For example:
+//
+//	set = {[0,5)}
+//	iv  = [3,8)
+//	result = {[0,3), [5,8)}
+//
+// Toggle(iv) = set △ {iv}
+func (set IntervalSet) Toggle(iv IntegerInterval) IntervalSet {
+	return set.SymmetricDifference(IntervalSet{iv})
+}
+
+// RelativeComplements returns set − other and other − set, each normalized.
+//
+// Together they are the two halves of the symmetric difference,
+// e.g. deletions and additions when going from set to other.
+//
+// For example:
+//
+//	a = {[0,5)}
+//	b = {[3,8)}
+//	aMinusB = {[0,3)}, bMinusA = {[5,8)}
+//
+// RelativeComplements(set') = (set − set', set' − set)
+func (set IntervalSet) RelativeComplements(other IntervalSet) (aMinusB, bMinusA IntervalSet) {
+	return set.SubtractSet(other), other.SubtractSet(set)
+}
+
+// DeltaFrom returns what has to be added to and removed from base to obtain the set,
+// each normalized. ApplyDelta reverses it, so a set can be stored as a delta against a similar base.
+//
+// For example:
+//
+//	set  = {[3,8)}
+//	base = {[0,5)}
+//	added = {[5,8)}, removed = {[0,3)}
+//
+// DeltaFrom(base) = (set − base, base − set)
+func (set IntervalSet) DeltaFrom(base IntervalSet) (added, removed IntervalSet) {
+	return set.SubtractSet(base), base.SubtractSet(set)
+}
+
+// ApplyDelta returns base with added covered and removed uncovered, normalized.
+//
+// base.ApplyDelta(set.DeltaFrom(base)) covers exactly what the set covers.
+//
+// ApplyDelta(added, removed) = (base ∪ added) − removed
+func (base IntervalSet) ApplyDelta(added, removed IntervalSet) IntervalSet {
+	return base.Union(added).SubtractSet(removed)
+}
+
+// IsSubsetOf reports whether every integer covered by the set is also covered by other.
+//
+// IsSubsetOf(set') ⇔ set ⊆ set'
+func (set IntervalSet) IsSubsetOf(other IntervalSet) bool {
+	return set.SubtractSet(other).TotalLength() == 0
+}
+
+// IsSupersetOf reports whether every integer covered by other is also covered by the set.
+//
+// IsSupersetOf(set') ⇔ set ⊇ set'
+func (set IntervalSet) IsSupersetOf(other IntervalSet) bool {
+	return other.IsSubsetOf(set)
+}
+
+// UnionAll returns the union of all the given sets.
+//
+// All intervals are concatenated and normalized once, in O(N log N) for N intervals in total,
+// instead of re-normalizing after every pairwise Union.
+//
+// For example:
+//
+//	sets   = {[0,2)}, {[1,4)}, {[6,8)}
+//	result = {[0,4), [6,8)}
+//
+// UnionAll(S₁, …, Sₖ) = Normalize(S₁ ∪ … ∪ Sₖ)
+func UnionAll(sets ...IntervalSet) IntervalSet {
+	total := 0
+	for _, set := range sets {
+		total += len(set)
+	}
+	combined := make(IntervalSet, 0, total)
+	for _, set := range sets {
+		combined = append(combined, set...)
+	}
+	return combined.Normalize()
+}
+
+// IntersectAll returns the integers covered by every one of the given sets.
+//
+// Returns nil for no sets and the normalization of the only set for one.
+// Each set is normalized once and folded in with a linear two-pointer intersection.
+//
+// For example:
+//
+//	sets   = {[0,10)}, {[2,8)}, {[5,12)}
+//	result = {[5,8)}
+//
+// IntersectAll(S₁, …, Sₖ) = S₁ ∩ … ∩ Sₖ
+func IntersectAll(sets ...IntervalSet) IntervalSet {
+	if len(sets) == 0 {
+		return nil
+	}
+	result := sets[0].Normalize()
+	for _, set := range sets[1:] {
+		if len(result) == 0 {
+			return nil
+		}
+		result = intersectNormalized(result, set.Normalize())
+	}
+	return result
+}
+
+// intersectNormalized は正規化済みの a, b の共通部分を二つのポインタで線形時間に求める。
+func intersectNormalized(a, b IntervalSet) IntervalSet {
+	var result IntervalSet
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		if intersection, ok := a[i].Intersect(b[j]); ok {
+			result = append(result, intersection)
+		}
+		if a[i].End < b[j].End {
+			i++
+		} else {
+			j++
+		}
+	}
+	return result
+}
+
+// Change kinds reported by DiffDetailed.
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+)
+
+// Change is a region that differs between two sets.
+type Change struct {
+	Interval IntegerInterval
+	Kind     string // ChangeAdded or ChangeRemoved
+}
+
+// DiffDetailed returns the changes going from the set to other, sorted by position:
+// regions covered only by other are ChangeAdded, regions covered only by the set are ChangeRemoved.
+//
+// For example:
+//
+//	a = {[0,5), [10,12)}
+//	b = {[3,8)}
+//	result = {[0,3) removed}, {[5,8) added}, {[10,12) removed}
+func (set IntervalSet) DiffDetailed(other IntervalSet) []Change {
+	removed, added := set.RelativeComplements(other)
+	result := make([]Change, 0, len(removed)+len(added))
+	for _, iv := range removed {
+		result = append(result, Change{Interval: iv, Kind: ChangeRemoved})
+	}
+	for _, iv := range added {
+		result = append(result, Change{Interval: iv, Kind: ChangeAdded})
+	}
+	slices.SortFunc(result, func(a, b Change) int {
+		return a.Interval.Compare(b.Interval)
+	})
+	return result
+}
+
+// sweepSet は a, b の端点を一度だけ走査し、keep(inA, inB) が真となる領域を
+// 正規化された区間集合として返す。
+func sweepSet(a, b IntervalSet, keep func(inA, inB bool) bool) IntervalSet {
+	events := sweepEndpoints(a, b)
+	var depth [2]int
+	var result IntervalSet
+	for i, e := range events {
+		depth[e.side] += e.delta
+		if i+1 == len(events) || events[i+1].pos == e.pos || !keep(depth[0] > 0, depth[1] > 0) {
+			continue
+		}
+		next := events[i+1].pos
+		if n := len(result); n > 0 && result[n-1].End == e.pos {
+			result[n-1].End = next
+		} else {
+			result = append(result, IntegerInterval{Start: e.pos, End: next})
+		}
+	}
+	return result
+}
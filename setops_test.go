@@ -0,0 +1,288 @@
+package interval
+
+import (
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestIntervalSet_ComplementIn(t *testing.T) {
+	tests := []struct {
+		set, universe, want IntervalSet
+	}{
+		{IntervalSet{{2, 12}}, IntervalSet{{0, 5}, {10, 15}}, IntervalSet{{0, 2}, {12, 15}}},
+		{IntervalSet{{1, 2}, {11, 12}}, IntervalSet{{10, 15}, {0, 5}}, IntervalSet{{0, 1}, {2, 5}, {10, 11}, {12, 15}}},
+		{IntervalSet{{0, 20}}, IntervalSet{{0, 5}, {10, 15}}, nil},
+		{nil, IntervalSet{{0, 3}, {3, 5}}, IntervalSet{{0, 5}}},
+	}
+	for _, tt := range tests {
+		if got := tt.set.ComplementIn(tt.universe); !slices.Equal(got, tt.want) {
+			t.Errorf("%v.ComplementIn(%v) = %v, want %v", tt.set, tt.universe, got, tt.want)
+		}
+	}
+}
+
+func TestIntervalSet_ComplementWithin(t *testing.T) {
+	bases := IntervalSet{{10, 15}, {0, 5}}
+	set := IntervalSet{{-10, 1}, {2, 12}, {20, 30}}
+	if got, want := set.ComplementWithin(bases), (IntervalSet{{1, 2}, {12, 15}}); !slices.Equal(got, want) {
+		t.Errorf("%v.ComplementWithin(%v) = %v, want %v", set, bases, got, want)
+	}
+}
+
+func TestIntervalSet_SubtractSet(t *testing.T) {
+	a := IntervalSet{{8, 10}, {0, 5}}
+	b := IntervalSet{{3, 9}}
+	if got, want := a.SubtractSet(b), (IntervalSet{{0, 3}, {9, 10}}); !slices.Equal(got, want) {
+		t.Errorf("%v.SubtractSet(%v) = %v, want %v", a, b, got, want)
+	}
+}
+
+func TestIntervalSet_SymmetricDifference(t *testing.T) {
+	a := IntervalSet{{0, 3}, {10, 12}}
+	b := IntervalSet{{2, 5}, {10, 12}}
+	if got, want := a.SymmetricDifference(b), (IntervalSet{{0, 2}, {3, 5}}); !slices.Equal(got, want) {
+		t.Errorf("%v.SymmetricDifference(%v) = %v, want %v", a, b, got, want)
+	}
+}
+
+func TestIntervalSet_Toggle(t *testing.T) {
+	set := IntervalSet{{0, 5}}
+	tests := []struct {
+		iv   IntegerInterval
+		want IntervalSet
+	}{
+		{IntegerInterval{3, 8}, IntervalSet{{0, 3}, {5, 8}}},
+		{IntegerInterval{1, 3}, IntervalSet{{0, 1}, {3, 5}}},
+		{IntegerInterval{7, 9}, IntervalSet{{0, 5}, {7, 9}}},
+		{IntegerInterval{5, 6}, IntervalSet{{0, 6}}},
+		{IntegerInterval{0, 5}, nil},
+	}
+	for _, tt := range tests {
+		if got := set.Toggle(tt.iv); !slices.Equal(got, tt.want) {
+			t.Errorf("%v.Toggle(%v) = %v, want %v", set, tt.iv, got, tt.want)
+		}
+	}
+}
+
+func TestIntervalSet_RelativeComplements(t *testing.T) {
+	r := rand.New(rand.NewPCG(5, 6))
+	for range 500 {
+		a, b := randomSet(r), randomSet(r)
+		aMinusB, bMinusA := a.RelativeComplements(b)
+		var xor []int
+		for n := range 60 {
+			if a.ContainsPoint(n) != b.ContainsPoint(n) {
+				xor = append(xor, n)
+			}
+		}
+		if got, want := aMinusB.Union(bMinusA), PointsToSet(xor); !slices.Equal(got, want) {
+			t.Fatalf("%v.RelativeComplements(%v) = %v, %v; union %v, want %v", a, b, aMinusB, bMinusA, got, want)
+		}
+		if len(aMinusB.Intersect(b)) != 0 || len(bMinusA.Intersect(a)) != 0 {
+			t.Fatalf("%v.RelativeComplements(%v) = %v, %v overlaps its inputs", a, b, aMinusB, bMinusA)
+		}
+	}
+}
+
+func TestIntervalSet_DeltaFrom(t *testing.T) {
+	added, removed := IntervalSet{{3, 8}}.DeltaFrom(IntervalSet{{0, 5}})
+	if !slices.Equal(added, IntervalSet{{5, 8}}) || !slices.Equal(removed, IntervalSet{{0, 3}}) {
+		t.Errorf("DeltaFrom = %v, %v", added, removed)
+	}
+	r := rand.New(rand.NewPCG(29, 30))
+	for range 500 {
+		base, target := randomSet(r), randomSet(r)
+		if got, want := base.ApplyDelta(target.DeltaFrom(base)), target.canonical(); !slices.Equal(got, want) {
+			t.Fatalf("%v.ApplyDelta(%v.DeltaFrom(base)) = %v, want %v", base, target, got, want)
+		}
+	}
+}
+
+func TestIntervalSet_FirstDifference(t *testing.T) {
+	tests := []struct {
+		a, b IntervalSet
+		pos  int
+		ok   bool
+	}{
+		{IntervalSet{{0, 5}, {8, 10}}, IntervalSet{{8, 10}, {0, 3}, {3, 5}}, 0, false},
+		{IntervalSet{{0, 5}}, IntervalSet{{1, 5}}, 0, true},
+		{IntervalSet{{0, 5}, {8, 10}}, IntervalSet{{0, 5}, {8, 11}}, 10, true},
+		{IntervalSet{{2, 2}}, nil, 0, false},
+	}
+	for _, tt := range tests {
+		if pos, ok := tt.a.FirstDifference(tt.b); pos != tt.pos || ok != tt.ok {
+			t.Errorf("%v.FirstDifference(%v) = %d, %v, want %d, %v", tt.a, tt.b, pos, ok, tt.pos, tt.ok)
+		}
+	}
+	r := rand.New(rand.NewPCG(43, 44))
+	for range 500 {
+		a, b := randomSet(r), randomSet(r)
+		diff := a.SymmetricDifference(b)
+		pos, ok := a.FirstDifference(b)
+		if ok != (len(diff) > 0) || ok && pos != diff[0].Start {
+			t.Fatalf("%v.FirstDifference(%v) = %d, %v, symmetric difference %v", a, b, pos, ok, diff)
+		}
+	}
+}
+
+func TestIntervalSet_IsSubsetOf(t *testing.T) {
+	tests := []struct {
+		a, b           IntervalSet
+		subset, supset bool
+	}{
+		{IntervalSet{{1, 2}}, IntervalSet{{0, 5}}, true, false},
+		{IntervalSet{{3, 7}}, IntervalSet{{0, 5}}, false, false},
+		{IntervalSet{{0, 2}, {2, 5}}, IntervalSet{{0, 5}}, true, true},
+		{IntervalSet{{0, 5}}, IntervalSet{{0, 2}, {3, 5}}, false, true},
+		{nil, IntervalSet{{0, 5}}, true, false},
+	}
+	for _, tt := range tests {
+		if got := tt.a.IsSubsetOf(tt.b); got != tt.subset {
+			t.Errorf("%v.IsSubsetOf(%v) = %v, want %v", tt.a, tt.b, got, tt.subset)
+		}
+		if got := tt.a.IsSupersetOf(tt.b); got != tt.supset {
+			t.Errorf("%v.IsSupersetOf(%v) = %v, want %v", tt.a, tt.b, got, tt.supset)
+		}
+	}
+}
+
+func TestUnionAll(t *testing.T) {
+	r := rand.New(rand.NewPCG(9, 10))
+	for range 200 {
+		a, b, c := randomSet(r), randomSet(r), randomSet(r)
+		if got, want := UnionAll(a, b, c), a.Union(b).Union(c); !slices.Equal(got, want) {
+			t.Fatalf("UnionAll(%v, %v, %v) = %v, want %v", a, b, c, got, want)
+		}
+	}
+	if got := UnionAll(); got != nil {
+		t.Errorf("UnionAll() = %v, want nil", got)
+	}
+}
+
+func benchmarkLayers() []IntervalSet {
+	r := rand.New(rand.NewPCG(11, 12))
+	layers := make([]IntervalSet, 32)
+	for i := range layers {
+		layers[i] = randomSet(r)
+	}
+	return layers
+}
+
+func BenchmarkUnionAll(b *testing.B) {
+	layers := benchmarkLayers()
+	b.ReportAllocs()
+	for range b.N {
+		benchSink = UnionAll(layers...)
+	}
+}
+
+func BenchmarkUnionPairwise(b *testing.B) {
+	layers := benchmarkLayers()
+	b.ReportAllocs()
+	for range b.N {
+		var union IntervalSet
+		for _, layer := range layers {
+			union = union.Union(layer)
+		}
+		benchSink = union
+	}
+}
+
+func TestIntersectAll(t *testing.T) {
+	a := IntervalSet{{0, 10}, {20, 30}}
+	b := IntervalSet{{2, 8}, {25, 40}}
+	c := IntervalSet{{5, 12}, {18, 27}}
+	if got, want := IntersectAll(a, b, c), (IntervalSet{{5, 8}, {25, 27}}); !slices.Equal(got, want) {
+		t.Errorf("IntersectAll = %v, want %v", got, want)
+	}
+	if got := IntersectAll(a, b, IntervalSet{{12, 18}}); len(got) != 0 {
+		t.Errorf("IntersectAll with a disjoint set = %v, want {}", got)
+	}
+	if got, want := IntersectAll(IntervalSet{{2, 4}, {0, 2}}), (IntervalSet{{0, 4}}); !slices.Equal(got, want) {
+		t.Errorf("IntersectAll(single) = %v, want %v", got, want)
+	}
+	if got := IntersectAll(); got != nil {
+		t.Errorf("IntersectAll() = %v, want nil", got)
+	}
+
+	r := rand.New(rand.NewPCG(13, 14))
+	for range 200 {
+		x, y, z := randomSet(r), randomSet(r), randomSet(r)
+		if got, want := IntersectAll(x, y, z), x.Intersect(y).Intersect(z); !slices.Equal(got, want) {
+			t.Fatalf("IntersectAll(%v, %v, %v) = %v, want %v", x, y, z, got, want)
+		}
+	}
+}
+
+func TestIntervalSet_DiffDetailed(t *testing.T) {
+	a := IntervalSet{{10, 12}, {0, 5}, {20, 22}}
+	b := IntervalSet{{3, 8}, {20, 22}, {15, 16}}
+	want := []Change{
+		{IntegerInterval{0, 3}, ChangeRemoved},
+		{IntegerInterval{5, 8}, ChangeAdded},
+		{IntegerInterval{10, 12}, ChangeRemoved},
+		{IntegerInterval{15, 16}, ChangeAdded},
+	}
+	if got := a.DiffDetailed(b); !slices.Equal(got, want) {
+		t.Errorf("DiffDetailed = %v, want %v", got, want)
+	}
+	if got := a.DiffDetailed(a); len(got) != 0 {
+		t.Errorf("DiffDetailed(itself) = %v, want none", got)
+	}
+}
+
+// coverageSet は [lo, hi) の各整数が set に覆われているかを map で返す、遅いが明らかに正しい参照モデル。
+func (set IntervalSet) coverageSet(lo, hi int) map[int]bool {
+	covered := make(map[int]bool)
+	for n := lo; n < hi; n++ {
+		for _, iv := range set {
+			if iv.Start <= n && n < iv.End {
+				covered[n] = true
+				break
+			}
+		}
+	}
+	return covered
+}
+
+func TestSetOperationsAgainstReference(t *testing.T) {
+	const lo, hi = -5, 60
+	r := rand.New(rand.NewPCG(47, 48))
+	for range 500 {
+		a, b := randomSet(r), randomSet(r)
+		start := r.IntN(50) - 3
+		iv := IntegerInterval{start, start + r.IntN(20)}
+		ca, cb := a.coverageSet(lo, hi), b.coverageSet(lo, hi)
+
+		model := func(keep func(n int) bool) map[int]bool {
+			covered := make(map[int]bool)
+			for n := lo; n < hi; n++ {
+				if keep(n) {
+					covered[n] = true
+				}
+			}
+			return covered
+		}
+		inIV := func(n int) bool { return iv.Start <= n && n < iv.End }
+		cases := []struct {
+			name string
+			got  IntervalSet
+			want map[int]bool
+		}{
+			{"Union", a.Union(b), model(func(n int) bool { return ca[n] || cb[n] })},
+			{"Intersect", a.Intersect(b), model(func(n int) bool { return ca[n] && cb[n] })},
+			{"SubtractSet", a.SubtractSet(b), model(func(n int) bool { return ca[n] && !cb[n] })},
+			{"SymmetricDifference", a.SymmetricDifference(b), model(func(n int) bool { return ca[n] != cb[n] })},
+			{"Subtract", a.Subtract(iv), model(func(n int) bool { return ca[n] && !inIV(n) })},
+			{"Complement", a.Complement(iv), model(func(n int) bool { return inIV(n) && !ca[n] })},
+			{"ComplementFast", a.ComplementFast(iv), model(func(n int) bool { return inIV(n) && !ca[n] })},
+		}
+		for _, c := range cases {
+			if got := c.got.coverageSet(lo, hi); !maps.Equal(got, c.want) {
+				t.Fatalf("%s of %v, %v, %v = %v disagrees with the reference model", c.name, a, b, iv, c.got)
+			}
+		}
+	}
+}
@@ -0,0 +1,619 @@
+package interval
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ExtractSlicesLenient returns the substrings of `text` for every in-range interval in the set,
+// together with the indices of the intervals that were skipped because they are invalid or out of range.
+//
+// Unlike ExtractSlices, a bad interval does not abort the whole extraction.
+//
+// For example:
+//
+//	text = "abcdef"
+//	set  = {[0,2), [4,9), [3,4)}
+//	result = ["ab", "d"], skipped = [1]
+func (set IntervalSet) ExtractSlicesLenient(text string) ([]string, []int) {
+	result := make([]string, 0, len(set))
+	var skipped []int
+	for i, iv := range set {
+		part, err := iv.Slice(text)
+		if err != nil {
+			skipped = append(skipped, i)
+			continue
+		}
+		result = append(result, part)
+	}
+	return result, skipped
+}
+
+// ExtractMerged normalizes the set and returns a substring of `text` for each merged interval.
+//
+// Adjacent or overlapping intervals yield a single substring, whereas ExtractSlices
+// returns one substring per original interval.
+// Returns an error if any merged interval is out of range.
+//
+// For example:
+//
+//	text = "abcdef"
+//	set  = {[0,2), [2,4)}
+//	result = ["abcd"]
+func (set IntervalSet) ExtractMerged(text string) ([]string, error) {
+	return set.Normalize().ExtractSlices(text)
+}
+
+// GapSlices returns the substrings of text not covered by the set, in order:
+// the gaps of the set within [0, len(text)).
+//
+// Returns an error if any interval is invalid (Start > End) or out of range.
+//
+// For example:
+//
+//	text = "abcdef"
+//	set  = {[1,2), [4,5)}
+//	result = ["a", "cd", "f"]
+func (set IntervalSet) GapSlices(text string) ([]string, error) {
+	if err := set.validWithin(len(text)); err != nil {
+		return nil, err
+	}
+	return set.ComplementFast(IntegerInterval{Start: 0, End: len(text)}).ExtractSlices(text)
+}
+
+// InvertText returns the complement of the set within the whole of text, i.e. within [0, len(text)).
+//
+// Parts of the set outside the text are clipped away rather than reported,
+// so the result always fits within the text.
+//
+// For example:
+//
+//	text = "abcdef"
+//	set  = {[1,2), [4,9)}
+//	result = {[0,1), [2,4)}
+func (set IntervalSet) InvertText(text string) IntervalSet {
+	return set.ComplementFast(IntegerInterval{Start: 0, End: len(text)})
+}
+
+// TextRegion is an interval of a text together with the substring it covers.
+type TextRegion struct {
+	Interval IntegerInterval
+	Text     string
+}
+
+// UncoveredRegions returns each gap of the set within [0, len(text)), in order,
+// together with the substring of text it covers.
+//
+// Returns an error if any interval is invalid (Start > End) or out of range.
+//
+// For example:
+//
+//	text = "abcdef"
+//	set  = {[1,2), [4,5)}
+//	result = {[0,1) "a"}, {[2,4) "cd"}, {[5,6) "f"}
+func (set IntervalSet) UncoveredRegions(text string) ([]TextRegion, error) {
+	if err := set.validWithin(len(text)); err != nil {
+		return nil, err
+	}
+	gaps := set.ComplementFast(IntegerInterval{Start: 0, End: len(text)})
+	result := make([]TextRegion, len(gaps))
+	for i, gap := range gaps {
+		result[i] = TextRegion{Interval: gap, Text: text[gap.Start:gap.End]}
+	}
+	return result, nil
+}
+
+// SplitText normalizes the set and splits text into its covered and uncovered pieces,
+// each list in positional order. Empty members are ignored.
+//
+// The two lists alternate in text: interleaving them, starting with covered if the first
+// covered region starts at 0, reconstructs text.
+// Returns an error if any interval is invalid (Start > End) or out of range.
+//
+// For example:
+//
+//	text = "abcdef"
+//	set  = {[1,2), [4,5)}
+//	covered = ["b", "e"], uncovered = ["a", "cd", "f"]
+func (set IntervalSet) SplitText(text string) (covered []string, uncovered []string, err error) {
+	if err := set.validWithin(len(text)); err != nil {
+		return nil, nil, err
+	}
+	canonical := set.canonical()
+	covered, err = canonical.ExtractSlices(text)
+	if err != nil {
+		return nil, nil, err
+	}
+	uncovered, err = canonical.ComplementFast(IntegerInterval{Start: 0, End: len(text)}).ExtractSlices(text)
+	if err != nil {
+		return nil, nil, err
+	}
+	return covered, uncovered, nil
+}
+
+// ZipExtract extracts the substrings of aSet from aText and of bSet from bText
+// and pairs them up by position.
+//
+// Returns an error if the sets differ in length or any interval is out of range.
+//
+// For example:
+//
+//	aSet = {[0,1), [2,3)}, aText = "abc"
+//	bSet = {[1,3), [0,1)}, bText = "xyz"
+//	result = [["a","yz"], ["c","x"]]
+func ZipExtract(aSet IntervalSet, aText string, bSet IntervalSet, bText string) ([][2]string, error) {
+	if len(aSet) != len(bSet) {
+		return nil, fmt.Errorf("set lengths differ: %d and %d", len(aSet), len(bSet))
+	}
+	aParts, err := aSet.ExtractSlices(aText)
+	if err != nil {
+		return nil, err
+	}
+	bParts, err := bSet.ExtractSlices(bText)
+	if err != nil {
+		return nil, err
+	}
+	result := make([][2]string, len(aParts))
+	for i := range aParts {
+		result[i] = [2]string{aParts[i], bParts[i]}
+	}
+	return result, nil
+}
+
+// Wrap normalizes the set and surrounds each covered region of `text` with prefix and suffix,
+// e.g. to highlight matches with ANSI color codes.
+//
+// Adjacent intervals are merged by normalization and therefore wrapped once.
+// Empty intervals cover nothing and are dropped, so they never produce a bare prefix+suffix.
+// Returns an error if any interval is invalid (Start > End) or out of range.
+//
+// For example:
+//
+//	text = "abcde"
+//	set  = {[1,3)}
+//	prefix, suffix = "<", ">"
+//	result = "a<bc>de"
+func (set IntervalSet) Wrap(text, prefix, suffix string) (string, error) {
+	var b strings.Builder
+	b.Grow(len(text) + len(set)*(len(prefix)+len(suffix)))
+	if err := set.WriteHighlighted(&b, text, prefix, suffix); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// WriteHighlighted is the streaming form of Wrap: it writes text to w with each covered region
+// of the normalized set surrounded by prefix and suffix, without building the result in memory.
+//
+// Every member is checked before anything is written: a member that is invalid (Start > End)
+// or out of range is an error, as in Wrap. Otherwise returns the first error from w, if any.
+func (set IntervalSet) WriteHighlighted(w io.Writer, text, prefix, suffix string) error {
+	if err := set.validWithin(len(text)); err != nil {
+		return err
+	}
+	last := 0
+	for _, iv := range set.canonical() {
+		for _, chunk := range [...]string{text[last:iv.Start], prefix, text[iv.Start:iv.End], suffix} {
+			if _, err := io.WriteString(w, chunk); err != nil {
+				return err
+			}
+		}
+		last = iv.End
+	}
+	_, err := io.WriteString(w, text[last:])
+	return err
+}
+
+// FindSubstring returns the interval of the first occurrence of needle in text.
+//
+// Returns false if needle is empty or not present.
+//
+// For example:
+//
+//	text = "banana", needle = "an" → [1,3)
+func FindSubstring(text, needle string) (IntegerInterval, bool) {
+	i := strings.Index(text, needle)
+	if i < 0 || needle == "" {
+		return IntegerInterval{}, false
+	}
+	return IntegerInterval{Start: i, End: i + len(needle)}, true
+}
+
+// FindAllSubstrings returns the intervals of all non-overlapping occurrences of needle in text,
+// scanning left to right like strings.Count.
+//
+// Returns nil if needle is empty or not present.
+//
+// For example:
+//
+//	text = "aaaa", needle = "aa" → {[0,2), [2,4)}
+func FindAllSubstrings(text, needle string) IntervalSet {
+	if needle == "" {
+		return nil
+	}
+	var result IntervalSet
+	for offset := 0; ; {
+		i := strings.Index(text[offset:], needle)
+		if i < 0 {
+			return result
+		}
+		start := offset + i
+		result = append(result, IntegerInterval{Start: start, End: start + len(needle)})
+		offset = start + len(needle)
+	}
+}
+
+// MaskComplement replaces every uncovered rune of `text` with mask, keeping covered text intact.
+//
+// Masking is per rune: each uncovered rune becomes one mask rune, whatever its byte length.
+// A rune counts as covered if its first byte is covered.
+// Returns an error if any interval is invalid (Start > End) or out of range.
+//
+// For example:
+//
+//	text = "abcde"
+//	set  = {[1,3)}
+//	mask = '*'
+//	result = "*bc**"
+func (set IntervalSet) MaskComplement(text string, mask rune) (string, error) {
+	return set.maskRunes(text, mask, false)
+}
+
+// Redact replaces every covered rune of `text` with mask, keeping uncovered text intact.
+//
+// Each covered rune becomes one mask rune, so the rune count and the positions
+// of the surrounding text (in runes) are unchanged. The set is normalized first,
+// so overlapping intervals are masked once.
+// A rune counts as covered if its first byte is covered.
+// Returns an error if any interval is invalid (Start > End) or out of range.
+//
+// For example:
+//
+//	text = "abcde"
+//	set  = {[1,3)}
+//	mask = '*'
+//	result = "a**de"
+func (set IntervalSet) Redact(text string, mask rune) (string, error) {
+	return set.maskRunes(text, mask, true)
+}
+
+// maskRunes は covered == masked となるルーンを mask で置き換える。
+func (set IntervalSet) maskRunes(text string, mask rune, masked bool) (string, error) {
+	if err := set.validWithin(len(text)); err != nil {
+		return "", err
+	}
+	normalized := set.Normalize()
+	var b strings.Builder
+	b.Grow(len(text))
+	j := 0
+	for i := 0; i < len(text); {
+		_, size := utf8.DecodeRuneInString(text[i:])
+		for j < len(normalized) && normalized[j].End <= i {
+			j++
+		}
+		covered := j < len(normalized) && normalized[j].Contains(i)
+		if covered == masked {
+			b.WriteRune(mask)
+		} else {
+			b.WriteString(text[i : i+size])
+		}
+		i += size
+	}
+	return b.String(), nil
+}
+
+// IntervalsWhere returns the byte intervals of each maximal run of runes in text satisfying pred.
+//
+// For example:
+//
+//	text = "  ab 12"
+//	pred = unicode.IsSpace
+//	result = {[0,2), [4,5)}
+func IntervalsWhere(text string, pred func(r rune) bool) IntervalSet {
+	var result IntervalSet
+	for i, r := range text {
+		if !pred(r) {
+			continue
+		}
+		end := i + utf8.RuneLen(r)
+		if r == utf8.RuneError {
+			_, size := utf8.DecodeRuneInString(text[i:])
+			end = i + size
+		}
+		if n := len(result); n > 0 && result[n-1].End == i {
+			result[n-1].End = end
+		} else {
+			result = append(result, IntegerInterval{Start: i, End: end})
+		}
+	}
+	return result
+}
+
+// WordIntervals returns the byte intervals of each word in text: each maximal run of
+// non-whitespace runes (unicode.IsSpace).
+//
+// For example:
+//
+//	text = "ab  cd"
+//	result = {[0,2), [4,6)}
+func WordIntervals(text string) IntervalSet {
+	return IntervalsWhere(text, func(r rune) bool {
+		return !unicode.IsSpace(r)
+	})
+}
+
+// IntervalsOfCategory returns the byte intervals of each maximal run of runes in text
+// that belong to rangeTab, such as unicode.Han or unicode.Latin.
+//
+// For example:
+//
+//	text = "Go言語"
+//	rangeTab = unicode.Han
+//	result = {[2,8)}
+func IntervalsOfCategory(text string, rangeTab *unicode.RangeTable) IntervalSet {
+	return IntervalsWhere(text, func(r rune) bool {
+		return unicode.Is(rangeTab, r)
+	})
+}
+
+// BalancedRegions returns the byte intervals of each outermost balanced open…close region
+// in text, delimiters included, in order. Nested regions lie inside their outermost one
+// and are not reported separately.
+//
+// If open and close are the same rune (such as '"' for CSV quoting), regions cannot nest
+// and each pair of delimiters forms one region.
+// Returns an error with the offending byte offset if a close has no matching open,
+// or an open is never closed.
+//
+// For example:
+//
+//	text = "a(b(c)d)e(f)"
+//	result = {[1,8), [9,12)}
+func BalancedRegions(text string, open, close rune) (IntervalSet, error) {
+	var result IntervalSet
+	depth, start := 0, 0
+	for i, r := range text {
+		switch {
+		case r == open && (open != close || depth == 0):
+			if depth == 0 {
+				start = i
+			}
+			depth++
+		case r == close:
+			if depth == 0 {
+				return nil, fmt.Errorf("unbalanced %q at offset %d", close, i)
+			}
+			depth--
+			if depth == 0 {
+				result = append(result, IntegerInterval{Start: start, End: i + utf8.RuneLen(r)})
+			}
+		}
+	}
+	if depth > 0 {
+		return nil, fmt.Errorf("unclosed %q at offset %d", open, start)
+	}
+	return result, nil
+}
+
+// ContextWindows grows every member of the set by ctx bytes on both sides, clamps it to
+// [0, len(text)) and returns the normalized result, so nearby members share one window.
+//
+// A negative ctx is treated as 0. Windows left empty by clamping are dropped.
+//
+// For example:
+//
+//	text = "0123456789abcdef", ctx = 2
+//	set  = {[5,6), [8,9), [14,15)}
+//	result = {[3,11), [12,16)}
+func (set IntervalSet) ContextWindows(text string, ctx int) IntervalSet {
+	ctx = max(ctx, 0)
+	windows := make(IntervalSet, 0, len(set))
+	for _, iv := range set {
+		windows = append(windows, IntegerInterval{
+			Start: max(iv.Start-ctx, 0),
+			End:   min(iv.End+ctx, len(text)),
+		})
+	}
+	windows = slices.DeleteFunc(windows, func(iv IntegerInterval) bool {
+		return !iv.IsValid()
+	})
+	return windows.canonical()
+}
+
+// DiffIntervals compares a and b byte by byte and returns the intervals of a that were
+// removed or changed and the intervals of b that were added or changed, each normalized.
+//
+// The common prefix and suffix are trimmed first, and the remaining middle parts are aligned
+// by a longest common subsequence; this costs O(n·m) time and memory in the sizes of the
+// middle parts, so it suits short or mostly similar strings. Identical strings give two nil sets.
+//
+// For example:
+//
+//	a = "hello world", b = "hello, wild"
+//	aChanged = {[7,9)}, bChanged = {[5,6), [8,9)}
+func DiffIntervals(a, b string) (aChanged, bChanged IntervalSet) {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	x, y := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+
+	// lcs[i][j] は x[i:] と y[j:] の最長共通部分列の長さ
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	mark := func(set *IntervalSet, pos int) {
+		if n := len(*set); n > 0 && (*set)[n-1].End == pos {
+			(*set)[n-1].End++
+		} else {
+			*set = append(*set, IntegerInterval{Start: pos, End: pos + 1})
+		}
+	}
+	i, j := 0, 0
+	for i < len(x) || j < len(y) {
+		switch {
+		case i < len(x) && j < len(y) && x[i] == y[j]:
+			i++
+			j++
+		case j == len(y) || (i < len(x) && lcs[i+1][j] >= lcs[i][j+1]):
+			mark(&aChanged, prefix+i)
+			i++
+		default:
+			mark(&bChanged, prefix+j)
+			j++
+		}
+	}
+	return aChanged, bChanged
+}
+
+// StableRegions returns the byte intervals where all of texts have the same byte,
+// e.g. the parts left untouched across several versions of a document.
+//
+// All texts must have the same length; otherwise an error naming the first mismatch is returned.
+// Returns nil for no texts.
+//
+// For example:
+//
+//	texts  = ["abcdef", "abXYef"]
+//	result = {[0,2), [4,6)}
+func StableRegions(texts []string) (IntervalSet, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+	for i, text := range texts[1:] {
+		if len(text) != len(texts[0]) {
+			return nil, fmt.Errorf("text %d has length %d, want %d", i+1, len(text), len(texts[0]))
+		}
+	}
+	return IntervalsFromPredicate(0, len(texts[0]), func(n int) bool {
+		for _, text := range texts[1:] {
+			if text[n] != texts[0][n] {
+				return false
+			}
+		}
+		return true
+	}), nil
+}
+
+// KeepOnly normalizes the set and returns the covered parts of `text` concatenated in order.
+//
+// Returns an error if any interval is out of range.
+//
+// For example:
+//
+//	text = "abcdef"
+//	set  = {[0,2), [4,6)}
+//	result = "abef"
+func (set IntervalSet) KeepOnly(text string) (string, error) {
+	parts, err := set.ExtractMerged(text)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(parts, ""), nil
+}
+
+// SliceRuneSafe is like Slice but widens the interval to rune boundaries first:
+// a Start inside a multibyte sequence moves back to the rune's first byte,
+// and an End inside one moves forward past the rune.
+//
+// For well-formed UTF-8 text the result is therefore always valid UTF-8.
+// Returns an error if the interval is out of bounds.
+//
+// For example:
+//
+//	text = "日本語" (3 bytes per rune)
+//	iv   = [1,4) → [0,6) → "日本"
+func (iv IntegerInterval) SliceRuneSafe(text string) (string, error) {
+	if !iv.IsValid() || iv.Start < 0 || iv.End > len(text) {
+		return "", errors.New("out of range")
+	}
+	start, end := iv.Start, iv.End
+	for start > 0 && start < len(text) && !utf8.RuneStart(text[start]) {
+		start--
+	}
+	for end < len(text) && !utf8.RuneStart(text[end]) {
+		end++
+	}
+	return text[start:end], nil
+}
+
+// InsertText inserts insert into text at byte offset at and returns the new text
+// together with the set adjusted to it.
+//
+// Members with Start ≥ at shift right by len(insert). A member straddling at
+// (Start < at < End) expands to include the inserted text. Members ending at or
+// before at are unchanged. Returns an error if at is outside [0, len(text)].
+//
+// For example:
+//
+//	text = "hello world", set = {[0,5), [3,8)}
+//	at = 5, insert = "XX" → "helloXX world", {[0,5), [3,10)}
+func (set IntervalSet) InsertText(text string, at int, insert string) (string, IntervalSet, error) {
+	updated, err := IntegerInterval{Start: at, End: at}.Insert(text, insert)
+	if err != nil {
+		return "", nil, err
+	}
+	result := set.ShiftAfter(at, len(insert))
+	for i, iv := range result {
+		if iv.Start < at && at < iv.End {
+			result[i].End += len(insert)
+		}
+	}
+	return updated, result, nil
+}
+
+// DeleteText removes del from text and returns the new text together with the set adjusted to it.
+//
+// Members before del are unchanged, members after it shift left by del.Length(),
+// and overlapping members are clipped by del. Members lying entirely inside del
+// collapse to empty and are dropped. Returns an error if del is out of bounds.
+//
+// For example:
+//
+//	text = "hello world", set = {[0,4), [2,8), [5,6), [8,11)}
+//	del  = [3,6) → "helworld", {[0,3), [2,5), [5,8)}
+func (set IntervalSet) DeleteText(text string, del IntegerInterval) (string, IntervalSet, error) {
+	updated, err := del.Remove(text)
+	if err != nil {
+		return "", nil, err
+	}
+	mapPos := func(p int) int {
+		switch {
+		case p <= del.Start:
+			return p
+		case p < del.End:
+			return del.Start
+		default:
+			return p - del.Length()
+		}
+	}
+	result := make(IntervalSet, 0, len(set))
+	for _, iv := range set {
+		moved := IntegerInterval{Start: mapPos(iv.Start), End: mapPos(iv.End)}
+		if moved.Length() <= 0 && iv.Length() > 0 {
+			continue
+		}
+		result = append(result, moved)
+	}
+	return updated, result, nil
+}
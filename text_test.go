@@ -0,0 +1,525 @@
+package interval
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+)
+
+func TestIntervalSet_ExtractSlicesLenient(t *testing.T) {
+	set := IntervalSet{{0, 2}, {4, 9}, {3, 4}, {2, 1}}
+	got, skipped := set.ExtractSlicesLenient("abcdef")
+	if want := []string{"ab", "d"}; !slices.Equal(got, want) {
+		t.Errorf("slices = %q, want %q", got, want)
+	}
+	if want := []int{1, 3}; !slices.Equal(skipped, want) {
+		t.Errorf("skipped = %v, want %v", skipped, want)
+	}
+}
+
+func TestIntervalSet_ExtractMerged(t *testing.T) {
+	tests := []struct {
+		set  IntervalSet
+		want []string
+	}{
+		{IntervalSet{{2, 4}, {0, 2}}, []string{"abcd"}},
+		{IntervalSet{{0, 2}, {3, 5}}, []string{"ab", "de"}},
+		{IntervalSet{{0, 3}, {1, 2}}, []string{"abc"}},
+	}
+	for _, tt := range tests {
+		got, err := tt.set.ExtractMerged("abcdef")
+		if err != nil || !slices.Equal(got, tt.want) {
+			t.Errorf("%v.ExtractMerged = %q, %v, want %q", tt.set, got, err, tt.want)
+		}
+	}
+	if _, err := (IntervalSet{{4, 9}}).ExtractMerged("abcdef"); err == nil {
+		t.Error("expected out of range error")
+	}
+}
+
+func TestZipExtract(t *testing.T) {
+	got, err := ZipExtract(IntervalSet{{0, 1}, {2, 3}}, "abc", IntervalSet{{1, 3}, {0, 1}}, "xyz")
+	want := [][2]string{{"a", "yz"}, {"c", "x"}}
+	if err != nil || !slices.Equal(got, want) {
+		t.Errorf("ZipExtract = %q, %v, want %q", got, err, want)
+	}
+	if _, err := ZipExtract(IntervalSet{{0, 1}}, "abc", nil, "xyz"); err == nil {
+		t.Error("expected length mismatch error")
+	}
+	if _, err := ZipExtract(IntervalSet{{0, 1}}, "abc", IntervalSet{{2, 4}}, "xyz"); err == nil {
+		t.Error("expected out of range error")
+	}
+}
+
+func TestIntervalSet_Wrap(t *testing.T) {
+	tests := []struct {
+		set  IntervalSet
+		want string
+	}{
+		{IntervalSet{{1, 3}}, "a<bc>de"},
+		{IntervalSet{{3, 5}, {0, 1}}, "<a>bc<de>"},
+		{IntervalSet{{1, 2}, {2, 3}}, "a<bc>de"}, // 隣接区間は正規化で1つになる
+		{IntervalSet{{2, 2}, {4, 5}}, "abcd<e>"}, // 空区間は何も囲まない
+		{nil, "abcde"},
+	}
+	for _, tt := range tests {
+		if got, err := tt.set.Wrap("abcde", "<", ">"); err != nil || got != tt.want {
+			t.Errorf("%v.Wrap = %q, %v, want %q", tt.set, got, err, tt.want)
+		}
+	}
+	for _, bad := range []IntervalSet{{{3, 6}}, {{4, 2}}, {{0, 1}, {4, 2}}} {
+		if _, err := bad.Wrap("abcde", "<", ">"); err == nil {
+			t.Errorf("%v.Wrap: expected an error", bad)
+		}
+	}
+}
+
+// failingWriter は n バイト書いた後の書き込みで失敗する。
+type failingWriter struct{ n int }
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if len(p) > w.n {
+		return 0, errors.New("disk full")
+	}
+	w.n -= len(p)
+	return len(p), nil
+}
+
+func TestIntervalSet_WriteHighlighted(t *testing.T) {
+	text := "the quick brown fox"
+	set := IntervalSet{{16, 19}, {4, 9}, {9, 10}}
+	var buf bytes.Buffer
+	if err := set.WriteHighlighted(&buf, text, "[", "]"); err != nil {
+		t.Fatal(err)
+	}
+	if want, _ := set.Wrap(text, "[", "]"); buf.String() != want {
+		t.Errorf("WriteHighlighted wrote %q, want %q", buf.String(), want)
+	}
+	if err := set.WriteHighlighted(&failingWriter{n: 6}, text, "[", "]"); err == nil || err.Error() != "disk full" {
+		t.Errorf("WriteHighlighted error = %v, want disk full", err)
+	}
+	buf.Reset()
+	if err := (IntervalSet{{15, 25}}).WriteHighlighted(&buf, text, "[", "]"); err == nil || buf.Len() != 0 {
+		t.Errorf("out of range: error = %v, wrote %q", err, buf.String())
+	}
+	buf.Reset()
+	if err := (IntervalSet{{0, 3}, {6, 2}}).WriteHighlighted(&buf, text, "[", "]"); err == nil || buf.Len() != 0 {
+		t.Errorf("reversed member: error = %v, wrote %q", err, buf.String())
+	}
+}
+
+func TestFindSubstring(t *testing.T) {
+	if got, ok := FindSubstring("banana", "an"); !ok || got != (IntegerInterval{1, 3}) {
+		t.Errorf("FindSubstring = %v, %v, want [1,3)", got, ok)
+	}
+	if _, ok := FindSubstring("banana", "x"); ok {
+		t.Error("FindSubstring found a missing needle")
+	}
+	if _, ok := FindSubstring("banana", ""); ok {
+		t.Error("FindSubstring found an empty needle")
+	}
+}
+
+func TestFindAllSubstrings(t *testing.T) {
+	tests := []struct {
+		text, needle string
+		want         IntervalSet
+	}{
+		{"banana", "an", IntervalSet{{1, 3}, {3, 5}}},
+		{"aaaa", "aa", IntervalSet{{0, 2}, {2, 4}}}, // 重なる出現 [1,3) は含まない
+		{"aaa", "aa", IntervalSet{{0, 2}}},
+		{"banana", "x", nil},
+		{"banana", "", nil},
+	}
+	for _, tt := range tests {
+		if got := FindAllSubstrings(tt.text, tt.needle); !slices.Equal(got, tt.want) {
+			t.Errorf("FindAllSubstrings(%q, %q) = %v, want %v", tt.text, tt.needle, got, tt.want)
+		}
+	}
+}
+
+func TestIntervalSet_MaskComplement(t *testing.T) {
+	tests := []struct {
+		set        IntervalSet
+		text, want string
+	}{
+		{IntervalSet{{1, 3}}, "abcde", "*bc**"},
+		{IntervalSet{{3, 6}}, "日本語です", "*本***"}, // 1ルーンにつき1つの mask
+		{nil, "abc", "***"},
+		{IntervalSet{{0, 3}}, "abc", "abc"},
+	}
+	for _, tt := range tests {
+		if got, err := tt.set.MaskComplement(tt.text, '*'); err != nil || got != tt.want {
+			t.Errorf("%v.MaskComplement(%q) = %q, %v, want %q", tt.set, tt.text, got, err, tt.want)
+		}
+	}
+	for _, bad := range []IntervalSet{{{2, 9}}, {{2, 1}}} {
+		if _, err := bad.MaskComplement("abc", '*'); err == nil {
+			t.Errorf("%v.MaskComplement: expected an error", bad)
+		}
+	}
+}
+
+func TestIntervalSet_Redact(t *testing.T) {
+	tests := []struct {
+		set        IntervalSet
+		text, want string
+	}{
+		{IntervalSet{{1, 3}}, "abcde", "a**de"},
+		{IntervalSet{{1, 3}, {2, 4}}, "abcde", "a***e"},
+		{IntervalSet{{3, 9}}, "日本語です", "日**です"},
+	}
+	for _, tt := range tests {
+		got, err := tt.set.Redact(tt.text, '*')
+		if err != nil || got != tt.want {
+			t.Errorf("%v.Redact(%q) = %q, %v, want %q", tt.set, tt.text, got, err, tt.want)
+		}
+		if utf8.RuneCountInString(got) != utf8.RuneCountInString(tt.text) {
+			t.Errorf("%v.Redact(%q) changed the rune count", tt.set, tt.text)
+		}
+	}
+	for _, bad := range []IntervalSet{{{3, 9}}, {{3, 1}}, {{0, 1}, {3, 1}}} {
+		if _, err := bad.Redact("abcde", '*'); err == nil {
+			t.Errorf("%v.Redact: expected an error", bad)
+		}
+	}
+}
+
+func TestIntervalsWhere(t *testing.T) {
+	text := " \tab 12c  345"
+	if got, want := IntervalsWhere(text, unicode.IsSpace), (IntervalSet{{0, 2}, {4, 5}, {8, 10}}); !slices.Equal(got, want) {
+		t.Errorf("IntervalsWhere(IsSpace) = %v, want %v", got, want)
+	}
+	if got, want := IntervalsWhere(text, unicode.IsDigit), (IntervalSet{{5, 7}, {10, 13}}); !slices.Equal(got, want) {
+		t.Errorf("IntervalsWhere(IsDigit) = %v, want %v", got, want)
+	}
+	if got, want := IntervalsWhere("a日本b", unicode.IsLetter), (IntervalSet{{0, 8}}); !slices.Equal(got, want) {
+		t.Errorf("IntervalsWhere(IsLetter) = %v, want %v", got, want)
+	}
+}
+
+func TestWordIntervals(t *testing.T) {
+	tests := []struct {
+		text string
+		want IntervalSet
+	}{
+		{"ab  cd", IntervalSet{{0, 2}, {4, 6}}},
+		{"  ab\t\ncd  ", IntervalSet{{2, 4}, {6, 8}}},
+		{"日本 語", IntervalSet{{0, 6}, {7, 10}}},
+		{"word", IntervalSet{{0, 4}}},
+		{"   ", nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := WordIntervals(tt.text); !slices.Equal(got, tt.want) {
+			t.Errorf("WordIntervals(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestIntervalsOfCategory(t *testing.T) {
+	text := "Go言語とRust"
+	han := IntervalsOfCategory(text, unicode.Han)
+	latin := IntervalsOfCategory(text, unicode.Latin)
+	hiragana := IntervalsOfCategory(text, unicode.Hiragana)
+	if want := (IntervalSet{{2, 8}}); !slices.Equal(han, want) {
+		t.Errorf("Han = %v, want %v", han, want)
+	}
+	if want := (IntervalSet{{0, 2}, {11, 15}}); !slices.Equal(latin, want) {
+		t.Errorf("Latin = %v, want %v", latin, want)
+	}
+	// 3 つの文字体系で全体を重なりなく分割する
+	all := append(append(slices.Clone(han), latin...), hiragana...)
+	if all.TotalLength() != len(text) || len(all.SelfIntersections()) != 0 {
+		t.Errorf("%v, %v and %v do not partition %q", han, latin, hiragana, text)
+	}
+}
+
+func TestIntervalSet_GapSlices(t *testing.T) {
+	tests := []struct {
+		set  IntervalSet
+		want []string
+	}{
+		{IntervalSet{{4, 5}, {1, 2}}, []string{"a", "cd", "f"}},
+		{IntervalSet{{0, 3}}, []string{"def"}},
+		{IntervalSet{{3, 6}}, []string{"abc"}},
+		{IntervalSet{{0, 4}, {2, 6}}, []string{}},
+		{nil, []string{"abcdef"}},
+	}
+	for _, tt := range tests {
+		got, err := tt.set.GapSlices("abcdef")
+		if err != nil || !slices.Equal(got, tt.want) {
+			t.Errorf("%v.GapSlices = %q, %v, want %q", tt.set, got, err, tt.want)
+		}
+	}
+	for _, bad := range []IntervalSet{{{4, 9}}, {{4, 2}}} {
+		if _, err := bad.GapSlices("abcdef"); err == nil {
+			t.Errorf("%v.GapSlices: expected an error", bad)
+		}
+	}
+}
+
+func TestIntervalSet_InvertText(t *testing.T) {
+	tests := []struct {
+		set  IntervalSet
+		want IntervalSet
+	}{
+		{IntervalSet{{2, 4}}, IntervalSet{{0, 2}, {4, 6}}},
+		{IntervalSet{{0, 1}, {5, 6}}, IntervalSet{{1, 5}}},
+		{IntervalSet{{-3, 1}, {4, 9}}, IntervalSet{{1, 4}}}, // はみ出した部分は切り捨てる
+		{IntervalSet{{0, 6}}, nil},
+		{nil, IntervalSet{{0, 6}}},
+	}
+	for _, tt := range tests {
+		got := tt.set.InvertText("abcdef")
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%v.InvertText = %v, want %v", tt.set, got, tt.want)
+		}
+		if whole := got.Union(tt.set.Intersect(IntervalSet{{0, 6}})); !slices.Equal(whole, IntervalSet{{0, 6}}) {
+			t.Errorf("%v.InvertText = %v does not toggle the whole text", tt.set, got)
+		}
+	}
+	if got := (IntervalSet{{0, 2}}).InvertText(""); len(got) != 0 {
+		t.Errorf("InvertText of empty text = %v", got)
+	}
+}
+
+func TestIntervalSet_UncoveredRegions(t *testing.T) {
+	text := "abcdefgh"
+	got, err := IntervalSet{{5, 7}, {2, 3}}.UncoveredRegions(text)
+	want := []TextRegion{
+		{IntegerInterval{0, 2}, "ab"},
+		{IntegerInterval{3, 5}, "de"},
+		{IntegerInterval{7, 8}, "h"},
+	}
+	if err != nil || !slices.Equal(got, want) {
+		t.Errorf("UncoveredRegions = %v, %v, want %v", got, err, want)
+	}
+	for _, r := range got {
+		if s, _ := r.Interval.Slice(text); s != r.Text {
+			t.Errorf("region %v has text %q, want %q", r.Interval, r.Text, s)
+		}
+	}
+	if got, err := (IntervalSet{{0, 8}}).UncoveredRegions(text); err != nil || len(got) != 0 {
+		t.Errorf("UncoveredRegions of full cover = %v, %v", got, err)
+	}
+	for _, bad := range []IntervalSet{{{6, 9}}, {{4, 2}}} {
+		if _, err := bad.UncoveredRegions(text); err == nil {
+			t.Errorf("%v.UncoveredRegions: expected an error", bad)
+		}
+	}
+}
+
+func TestIntervalSet_SplitText(t *testing.T) {
+	text := "abcdefgh"
+	tests := []struct {
+		set                IntervalSet
+		covered, uncovered []string
+	}{
+		{IntervalSet{{4, 5}, {1, 2}}, []string{"b", "e"}, []string{"a", "cd", "fgh"}},
+		{IntervalSet{{0, 3}, {2, 4}, {6, 8}}, []string{"abcd", "gh"}, []string{"ef"}},
+		{IntervalSet{{3, 3}}, []string{}, []string{"abcdefgh"}},
+	}
+	for _, tt := range tests {
+		covered, uncovered, err := tt.set.SplitText(text)
+		if err != nil || !slices.Equal(covered, tt.covered) || !slices.Equal(uncovered, tt.uncovered) {
+			t.Errorf("%v.SplitText = %q, %q, %v, want %q, %q", tt.set, covered, uncovered, err, tt.covered, tt.uncovered)
+			continue
+		}
+		// 先頭が覆われているかどうかで交互に並べれば元の文字列になる
+		first, second := uncovered, covered
+		if tt.set.ContainsPoint(0) {
+			first, second = covered, uncovered
+		}
+		var b strings.Builder
+		for i := range max(len(first), len(second)) {
+			if i < len(first) {
+				b.WriteString(first[i])
+			}
+			if i < len(second) {
+				b.WriteString(second[i])
+			}
+		}
+		if b.String() != text {
+			t.Errorf("%v: interleaving %q and %q gives %q", tt.set, covered, uncovered, b.String())
+		}
+	}
+	for _, bad := range []IntervalSet{{{6, 9}}, {{4, 2}}} {
+		if _, _, err := bad.SplitText(text); err == nil {
+			t.Errorf("%v.SplitText: expected an error", bad)
+		}
+	}
+}
+
+func TestBalancedRegions(t *testing.T) {
+	tests := []struct {
+		text        string
+		open, close rune
+		want        IntervalSet
+	}{
+		{"a(b(c)d)e", '(', ')', IntervalSet{{1, 8}}},
+		{"(a)(b(c))x", '(', ')', IntervalSet{{0, 3}, {3, 9}}},
+		{"「日本」と「語」", '「', '」', IntervalSet{{0, 12}, {15, 24}}},
+		{`a,"b,c",d,""`, '"', '"', IntervalSet{{2, 7}, {10, 12}}},
+		{"none", '(', ')', nil},
+	}
+	for _, tt := range tests {
+		got, err := BalancedRegions(tt.text, tt.open, tt.close)
+		if err != nil || !slices.Equal(got, tt.want) {
+			t.Errorf("BalancedRegions(%q) = %v, %v, want %v", tt.text, got, err, tt.want)
+		}
+	}
+	for _, tt := range []struct {
+		text        string
+		open, close rune
+		offset      string
+	}{
+		{"(a))b", '(', ')', "offset 3"},
+		{"a(b(c)", '(', ')', "offset 1"},
+		{`"a","b`, '"', '"', "offset 4"},
+	} {
+		if _, err := BalancedRegions(tt.text, tt.open, tt.close); err == nil || !strings.Contains(err.Error(), tt.offset) {
+			t.Errorf("BalancedRegions(%q) error = %v, want one mentioning %s", tt.text, err, tt.offset)
+		}
+	}
+}
+
+func TestIntervalSet_ContextWindows(t *testing.T) {
+	text := "0123456789abcdef"
+	tests := []struct {
+		set  IntervalSet
+		ctx  int
+		want IntervalSet
+	}{
+		{IntervalSet{{5, 6}, {8, 9}}, 2, IntervalSet{{3, 11}}},
+		{IntervalSet{{5, 6}, {8, 9}}, 1, IntervalSet{{4, 10}}},
+		{IntervalSet{{2, 3}, {10, 11}}, 2, IntervalSet{{0, 5}, {8, 13}}},
+		{IntervalSet{{14, 15}, {20, 22}}, 3, IntervalSet{{11, 16}}},
+		{IntervalSet{{4, 6}}, -1, IntervalSet{{4, 6}}},
+	}
+	for _, tt := range tests {
+		if got := tt.set.ContextWindows(text, tt.ctx); !slices.Equal(got, tt.want) {
+			t.Errorf("%v.ContextWindows(%d) = %v, want %v", tt.set, tt.ctx, got, tt.want)
+		}
+	}
+}
+
+func TestDiffIntervals(t *testing.T) {
+	tests := []struct {
+		a, b         string
+		aWant, bWant IntervalSet
+	}{
+		{"same", "same", nil, nil},
+		{"", "", nil, nil},
+		{"helloworld", "hello world", nil, IntervalSet{{5, 6}}},
+		{"abcXYZdef", "abc12def", IntervalSet{{3, 6}}, IntervalSet{{3, 5}}},
+		{"hello world", "hello, wild", IntervalSet{{7, 9}}, IntervalSet{{5, 6}, {8, 9}}},
+		{"abc", "", IntervalSet{{0, 3}}, nil},
+	}
+	for _, tt := range tests {
+		aChanged, bChanged := DiffIntervals(tt.a, tt.b)
+		if !slices.Equal(aChanged, tt.aWant) || !slices.Equal(bChanged, tt.bWant) {
+			t.Errorf("DiffIntervals(%q, %q) = %v, %v, want %v, %v", tt.a, tt.b, aChanged, bChanged, tt.aWant, tt.bWant)
+		}
+		// 変更のない部分は両方で一致する
+		aKept, _ := aChanged.ComplementFast(IntegerInterval{0, len(tt.a)}).KeepOnly(tt.a)
+		bKept, _ := bChanged.ComplementFast(IntegerInterval{0, len(tt.b)}).KeepOnly(tt.b)
+		if aKept != bKept {
+			t.Errorf("DiffIntervals(%q, %q): unchanged parts differ", tt.a, tt.b)
+		}
+	}
+}
+
+func TestStableRegions(t *testing.T) {
+	tests := []struct {
+		texts []string
+		want  IntervalSet
+	}{
+		{[]string{"abcdef", "abXYef"}, IntervalSet{{0, 2}, {4, 6}}},
+		{[]string{"0123456789", "0x23456789", "012345y789", "0123456z89"}, IntervalSet{{0, 1}, {2, 6}, {8, 10}}},
+		{[]string{"same"}, IntervalSet{{0, 4}}},
+		{[]string{"ab", "xy"}, nil},
+		{nil, nil},
+	}
+	for _, tt := range tests {
+		if got, err := StableRegions(tt.texts); err != nil || !slices.Equal(got, tt.want) {
+			t.Errorf("StableRegions(%q) = %v, %v, want %v", tt.texts, got, err, tt.want)
+		}
+	}
+	if _, err := StableRegions([]string{"abc", "abc", "ab"}); err == nil {
+		t.Error("expected error for length mismatch")
+	}
+}
+
+func TestIntervalSet_KeepOnly(t *testing.T) {
+	tests := []struct {
+		set  IntervalSet
+		want string
+	}{
+		{IntervalSet{{0, 2}, {4, 6}}, "abef"},
+		{IntervalSet{{1, 4}, {2, 5}}, "bcde"},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		if got, err := tt.set.KeepOnly("abcdef"); err != nil || got != tt.want {
+			t.Errorf("%v.KeepOnly = %q, %v, want %q", tt.set, got, err, tt.want)
+		}
+	}
+	if _, err := (IntervalSet{{5, 7}}).KeepOnly("abcdef"); err == nil {
+		t.Error("expected out of range error")
+	}
+}
+
+func TestIntegerInterval_SliceRuneSafe(t *testing.T) {
+	text := "日本語abc" // 日[0,3) 本[3,6) 語[6,9)
+	tests := []struct {
+		iv   IntegerInterval
+		want string
+	}{
+		{IntegerInterval{1, 4}, "日本"},
+		{IntegerInterval{4, 8}, "本語"},
+		{IntegerInterval{3, 6}, "本"},
+		{IntegerInterval{7, 10}, "語a"},
+		{IntegerInterval{4, 4}, "本"},
+	}
+	for _, tt := range tests {
+		got, err := tt.iv.SliceRuneSafe(text)
+		if err != nil || got != tt.want || !utf8.ValidString(got) {
+			t.Errorf("%v.SliceRuneSafe = %q, %v, want %q", tt.iv, got, err, tt.want)
+		}
+	}
+	if _, err := (IntegerInterval{5, 20}).SliceRuneSafe(text); err == nil {
+		t.Error("expected out of range error")
+	}
+}
+
+func TestIntervalSet_InsertText(t *testing.T) {
+	set := IntervalSet{{0, 5}, {3, 8}, {6, 9}}
+	text, got, err := set.InsertText("hello world", 5, "XX")
+	want := IntervalSet{{0, 5}, {3, 10}, {8, 11}}
+	if err != nil || text != "helloXX world" || !slices.Equal(got, want) {
+		t.Errorf("InsertText = %q, %v, %v, want %v", text, got, err, want)
+	}
+	if s, _ := got[1].Slice(text); s != "loXX wo" {
+		t.Errorf("straddling annotation = %q, want %q", s, "loXX wo")
+	}
+	if _, _, err := set.InsertText("abc", 4, "x"); err == nil {
+		t.Error("expected error for insertion past end")
+	}
+}
+
+func TestIntervalSet_DeleteText(t *testing.T) {
+	set := IntervalSet{{0, 4}, {2, 8}, {4, 6}, {8, 11}}
+	text, got, err := set.DeleteText("hello world", IntegerInterval{3, 6})
+	want := IntervalSet{{0, 3}, {2, 5}, {5, 8}}
+	if err != nil || text != "helworld" || !slices.Equal(got, want) {
+		t.Errorf("DeleteText = %q, %v, %v, want %v", text, got, err, want)
+	}
+	if _, _, err := set.DeleteText("abc", IntegerInterval{1, 5}); err == nil {
+		t.Error("expected error for deletion out of range")
+	}
+}
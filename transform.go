@@ -0,0 +1,618 @@
+package interval
+
+import (
+	"cmp"
+	"math"
+	"slices"
+)
+
+// Snap rounds Start and End to the nearest multiple of grid (halves round up).
+//
+// Unlike growing outward, each endpoint moves independently, so a short interval
+// may collapse to an empty one. If the rounded Start would exceed the rounded End
+// (only possible for an invalid iv), an empty interval at the rounded Start is returned.
+// If grid ≤ 0, iv is returned unchanged.
+//
+// For example:
+//
+//	iv = [3,17), grid = 10 → [0,20)
+//	iv = [5,15), grid = 10 → [10,20)
+//	iv = [6,8),  grid = 10 → [10,10)
+//
+// Snap(g) = [round(Start/g)·g, round(End/g)·g)
+func (iv IntegerInterval) Snap(grid int) IntegerInterval {
+	if grid <= 0 {
+		return iv
+	}
+	start := roundToGrid(iv.Start, grid)
+	end := roundToGrid(iv.End, grid)
+	if start > end {
+		return IntegerInterval{Start: start, End: start}
+	}
+	return IntegerInterval{Start: start, End: end}
+}
+
+// Downsample projects the set onto a grid of cells [k·cellSize, (k+1)·cellSize) and returns the
+// cells covered by at least minFraction of their length, merged into runs of consecutive cells.
+//
+// A cell with no coverage is never kept, so minFraction ≤ 0 keeps every cell the set touches.
+// Returns nil if cellSize ≤ 0.
+//
+// For example:
+//
+//	set = {[0,3), [12,14)}, cellSize = 4
+//	minFraction = 0   → {[0,4), [12,16)}
+//	minFraction = 0.6 → {[0,4)}
+func (set IntervalSet) Downsample(cellSize int, minFraction float64) IntervalSet {
+	if cellSize <= 0 {
+		return nil
+	}
+	var result IntervalSet
+	cell, covered := 0, 0 // 集計中のセルとその被覆長（covered = 0 なら未集計）
+	flush := func() {
+		if covered == 0 || float64(covered) < minFraction*float64(cellSize) {
+			return
+		}
+		start := cell * cellSize
+		if n := len(result); n > 0 && result[n-1].End == start {
+			result[n-1].End += cellSize
+		} else {
+			result = append(result, IntegerInterval{Start: start, End: start + cellSize})
+		}
+	}
+	for _, iv := range set.canonical() {
+		for k := floorDiv(iv.Start, cellSize); k*cellSize < iv.End; k++ {
+			if covered > 0 && k != cell {
+				flush()
+				covered = 0
+			}
+			cell = k
+			overlap, _ := iv.Intersect(IntegerInterval{Start: k * cellSize, End: (k + 1) * cellSize})
+			covered += overlap.Length()
+		}
+	}
+	flush()
+	return result
+}
+
+// roundToGrid は n を最も近い grid の倍数に丸める（ちょうど中間なら大きい方へ）。
+func roundToGrid(n, grid int) int {
+	return floorDiv(n+grid/2, grid) * grid
+}
+
+// floorDiv は負の数でも −∞ 方向に切り捨てる整数除算。
+func floorDiv(a, b int) int {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
+
+// Duplicates returns the intervals that appear more than once in the set,
+// each reported once, in order of first appearance.
+//
+// Only exact duplicates count; overlapping or adjacent intervals are not reported.
+//
+// For example:
+//
+//	set    = {[0,2), [1,3), [0,2), [0,2)}
+//	result = {[0,2)}
+func (set IntervalSet) Duplicates() IntervalSet {
+	counts := make(map[IntegerInterval]int, len(set))
+	var result IntervalSet
+	for _, iv := range set {
+		counts[iv]++
+		if counts[iv] == 2 {
+			result = append(result, iv)
+		}
+	}
+	return result
+}
+
+// Dedup returns the set with exact duplicates removed, keeping the first occurrence of each.
+//
+// Unlike Normalize, overlapping or adjacent intervals are left untouched and order is preserved.
+//
+// For example:
+//
+//	set    = {[0,2), [1,3), [0,2)}
+//	result = {[0,2), [1,3)}
+func (set IntervalSet) Dedup() IntervalSet {
+	seen := make(map[IntegerInterval]bool, len(set))
+	result := make(IntervalSet, 0, len(set))
+	for _, iv := range set {
+		if !seen[iv] {
+			seen[iv] = true
+			result = append(result, iv)
+		}
+	}
+	return result
+}
+
+// RemoveContained drops every member covered by another member, keeping the rest in their
+// original order. Of exact duplicates the first is kept.
+//
+// Unlike Normalize, nothing is merged: members that overlap without one containing the other
+// are both kept as they are. Members are examined in order of Start (ties: longest first),
+// so this is O(n log n).
+//
+// For example:
+//
+//	set    = {[0,10), [2,5), [12,14), [13,16)}
+//	result = {[0,10), [12,14), [13,16)}
+func (set IntervalSet) RemoveContained() IntervalSet {
+	order := make([]int, len(set))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return cmp.Or(cmp.Compare(set[a].Start, set[b].Start), cmp.Compare(set[b].End, set[a].End))
+	})
+	contained := make([]bool, len(set))
+	maxEnd := 0
+	for k, i := range order {
+		// 先に見た区間はどれも Start が set[i].Start 以下なので、End だけ比べればよい
+		if k > 0 && set[i].End <= maxEnd {
+			contained[i] = true
+			continue
+		}
+		maxEnd = set[i].End
+	}
+	var result IntervalSet
+	for i, iv := range set {
+		if !contained[i] {
+			result = append(result, iv)
+		}
+	}
+	return result
+}
+
+// Outermost returns the top-level regions of the set: the normalized set with empty members dropped.
+//
+// Normalization merges overlapping members into their common span, so whatever is nested inside
+// disappears into it; members that touch are merged as well. Unlike RemoveContained, which keeps
+// the containing member exactly, partially overlapping members become one span.
+//
+// For example:
+//
+//	set    = {[0,10), [2,5), [8,14), [14,16)}
+//	result = {[0,16)}
+func (set IntervalSet) Outermost() IntervalSet {
+	return set.canonical()
+}
+
+// WithStart returns a copy of iv with Start replaced by s.
+//
+// The result is not validated, so intermediate invalid states are allowed.
+func (iv IntegerInterval) WithStart(s int) IntegerInterval {
+	iv.Start = s
+	return iv
+}
+
+// WithEnd returns a copy of iv with End replaced by e.
+//
+// The result is not validated, so intermediate invalid states are allowed.
+func (iv IntegerInterval) WithEnd(e int) IntegerInterval {
+	iv.End = e
+	return iv
+}
+
+// Simplify returns the normalized set reduced to at most maxCount intervals.
+//
+// Empty members cover nothing and are dropped first, so they neither count toward maxCount
+// nor pull a merge across a gap.
+//
+// While there are too many intervals, the two neighbours separated by the smallest gap
+// are merged (the earliest gap wins ties), so the gaps bridged are as small as possible.
+// Returns nil if maxCount ≤ 0.
+//
+// For example:
+//
+//	set      = {[0,2), [3,5), [10,12), [20,22), [23,25)}
+//	maxCount = 2
+//	result   = {[0,12), [20,25)}
+func (set IntervalSet) Simplify(maxCount int) IntervalSet {
+	if maxCount <= 0 {
+		return nil
+	}
+	result := set.canonical()
+	for len(result) > maxCount {
+		best := 0
+		for i := 1; i+1 < len(result); i++ {
+			if result[i+1].Start-result[i].End < result[best+1].Start-result[best].End {
+				best = i
+			}
+		}
+		result[best].End = result[best+1].End
+		result = slices.Delete(result, best+1, best+2)
+	}
+	return result
+}
+
+// Rebase translates the set so that its minimum Start becomes 0,
+// returning the shifted set and the offset that was subtracted.
+//
+// Order is preserved and nothing is merged. Returns (nil, 0) for an empty set.
+//
+// For example:
+//
+//	set    = {[5,7), [9,10)}
+//	result = {[0,2), [4,5)}, offset = 5
+//
+// Rebase() = ({[s − m, e − m) | [s, e) ∈ set}, m), m = min Start
+func (set IntervalSet) Rebase() (IntervalSet, int) {
+	if len(set) == 0 {
+		return nil, 0
+	}
+	offset := set[0].Start
+	for _, iv := range set[1:] {
+		offset = min(offset, iv.Start)
+	}
+	result := make(IntervalSet, len(set))
+	for i, iv := range set {
+		result[i] = IntegerInterval{Start: iv.Start - offset, End: iv.End - offset}
+	}
+	return result, offset
+}
+
+// SortedBy returns a copy of the set sorted by less, leaving the set unchanged.
+//
+// The sort is stable, so intervals that compare equal keep their relative order.
+//
+// For example:
+//
+//	set  = {[0,3), [1,8), [2,5)}
+//	less = func(a, b IntegerInterval) bool { return a.End > b.End }
+//	result = {[1,8), [2,5), [0,3)}
+func (set IntervalSet) SortedBy(less func(a, b IntegerInterval) bool) IntervalSet {
+	sorted := slices.Clone(set)
+	slices.SortStableFunc(sorted, func(a, b IntegerInterval) int {
+		switch {
+		case less(a, b):
+			return -1
+		case less(b, a):
+			return 1
+		}
+		return 0
+	})
+	return sorted
+}
+
+// SplitBy cuts every member of the set at each Start and End of other that falls strictly inside it,
+// so that no resulting interval crosses a boundary of other.
+//
+// Members are processed in order and nothing is merged.
+//
+// For example:
+//
+//	set    = {[0,10)}
+//	other  = {[3,7)}
+//	result = {[0,3), [3,7), [7,10)}
+func (set IntervalSet) SplitBy(other IntervalSet) IntervalSet {
+	cuts := make([]int, 0, 2*len(other))
+	for _, iv := range other {
+		cuts = append(cuts, iv.Start, iv.End)
+	}
+	slices.Sort(cuts)
+	cuts = slices.Compact(cuts)
+
+	result := make(IntervalSet, 0, len(set))
+	for _, iv := range set {
+		i, _ := slices.BinarySearch(cuts, iv.Start+1)
+		start := iv.Start
+		for ; i < len(cuts) && cuts[i] < iv.End; i++ {
+			result = append(result, IntegerInterval{Start: start, End: cuts[i]})
+			start = cuts[i]
+		}
+		result = append(result, IntegerInterval{Start: start, End: iv.End})
+	}
+	return result
+}
+
+// ShiftAfter shifts every member whose Start ≥ pivot by delta, leaving the others untouched.
+//
+// This models an insertion (delta > 0) or deletion (delta < 0) at pivot when intervals
+// straddling pivot do not matter: a member with Start < pivot < End is left as is,
+// neither shifted nor stretched. Order is preserved and nothing is merged.
+//
+// For example:
+//
+//	set    = {[0,2), [3,7), [5,6)}
+//	pivot  = 4, delta = 10
+//	result = {[0,2), [3,7), [15,16)}
+func (set IntervalSet) ShiftAfter(pivot, delta int) IntervalSet {
+	result := slices.Clone(set)
+	for i, iv := range result {
+		if iv.Start >= pivot {
+			result[i] = IntegerInterval{Start: iv.Start + delta, End: iv.End + delta}
+		}
+	}
+	return result
+}
+
+// EnforceSeparation sorts the set and moves members right, keeping their lengths, until each
+// starts at least minSep after the End of the one before it. It also returns how far the last
+// member was moved.
+//
+// Members are only ever moved right, and only as far as needed: a member that is pushed may in
+// turn push the next one, but members already far enough away stay put. A negative minSep is
+// treated as 0, which just pushes overlapping members apart.
+//
+// For example:
+//
+//	set    = {[0,3), [3,5), [6,8), [20,22)}
+//	minSep = 2
+//	result = {[0,3), [5,7), [9,11), [20,22)}, 0
+func (set IntervalSet) EnforceSeparation(minSep int) (IntervalSet, int) {
+	minSep = max(minSep, 0)
+	result := slices.Clone(set)
+	slices.SortFunc(result, IntegerInterval.Compare)
+	shift := 0
+	for i := 1; i < len(result); i++ {
+		shift = max(result[i-1].End+minSep-result[i].Start, 0)
+		result[i].Start += shift
+		result[i].End += shift
+	}
+	return result, shift
+}
+
+// DilateToStable grows every non-empty member by radius on both sides and merges the result,
+// returning the clusters and the number of dilation passes needed, which is always 1.
+//
+// Dilation itself is not idempotent: dilating the result again grows every cluster by another
+// radius. What is settled after one pass is the clustering: two members share a cluster exactly
+// when a chain of members links them with gaps of at most 2·radius, and no further pass changes
+// that, because eroding the clusters by radius and dilating them again gives back the same clusters.
+// A negative radius is treated as 0.
+//
+// For example:
+//
+//	set    = {[0,2), [5,6), [20,21)}
+//	radius = 2
+//	result = {[-2,8), [18,23)}, 1
+func (set IntervalSet) DilateToStable(radius int) (IntervalSet, int) {
+	radius = max(radius, 0)
+	canonical := set.canonical()
+	dilated := make(IntervalSet, len(canonical))
+	for i, iv := range canonical {
+		dilated[i] = IntegerInterval{Start: iv.Start - radius, End: iv.End + radius}
+	}
+	return dilated.Normalize(), 1
+}
+
+// Edges returns, for each merged region of the set, its first and last position as width-1
+// intervals [Start, Start+1) and [End−1, End), e.g. to style the border characters of a region.
+//
+// A region of length 1 yields a single edge. The result is sorted, but adjacent edges are
+// not merged, so each edge stays its own member. Empty members are ignored.
+//
+// For example:
+//
+//	set    = {[2,6), [8,9)}
+//	result = {[2,3), [5,6), [8,9)}
+func (set IntervalSet) Edges() IntervalSet {
+	var result IntervalSet
+	for _, iv := range set.canonical() {
+		result = append(result, IntegerInterval{Start: iv.Start, End: iv.Start + 1})
+		if iv.Length() > 1 {
+			result = append(result, IntegerInterval{Start: iv.End - 1, End: iv.End})
+		}
+	}
+	return result
+}
+
+// Lerp linearly interpolates between iv (t = 0) and other (t = 1).
+//
+// Each endpoint is rounded to the nearest integer, halves away from zero (math.Round).
+// t is not clamped, so values outside [0,1] extrapolate.
+//
+// For example:
+//
+//	iv = [0,10), other = [10,15)
+//	t = 0.5 → [5,13)
+//
+// Lerp(other, t) = [round(Start + t·(other.Start − Start)), round(End + t·(other.End − End)))
+func (iv IntegerInterval) Lerp(other IntegerInterval, t float64) IntegerInterval {
+	return IntegerInterval{
+		Start: lerp(iv.Start, other.Start, t),
+		End:   lerp(iv.End, other.End, t),
+	}
+}
+
+// Tween interpolates from a to b over frames frames and returns the set at each frame:
+// frame 0 is a, the last frame is b, and frame f lies at t = f / (frames − 1) in between.
+//
+// Members of a and b are sorted (not merged) and matched by index with Lerp.
+// If one set has fewer members, it is padded with zero-length intervals placed at the
+// Start of their counterparts, so extra members grow out of, or shrink into, a point.
+// Each frame is normalized, with empty members dropped. Returns nil if frames ≤ 0,
+// and just {a} if frames = 1.
+//
+// For example:
+//
+//	a = {[0,2)}, b = {[0,8)}, frames = 3
+//	result = {[0,2)}, {[0,5)}, {[0,8)}
+func Tween(a, b IntervalSet, frames int) []IntervalSet {
+	if frames <= 0 {
+		return nil
+	}
+	from := slices.Clone(a)
+	to := slices.Clone(b)
+	slices.SortFunc(from, IntegerInterval.Compare)
+	slices.SortFunc(to, IntegerInterval.Compare)
+	for len(from) < len(to) {
+		s := to[len(from)].Start
+		from = append(from, IntegerInterval{Start: s, End: s})
+	}
+	for len(to) < len(from) {
+		s := from[len(to)].Start
+		to = append(to, IntegerInterval{Start: s, End: s})
+	}
+
+	result := make([]IntervalSet, frames)
+	for f := range result {
+		t := 0.0
+		if frames > 1 {
+			t = float64(f) / float64(frames-1)
+		}
+		frame := make(IntervalSet, len(from))
+		for i := range from {
+			frame[i] = from[i].Lerp(to[i], t)
+		}
+		result[f] = frame.canonical()
+	}
+	return result
+}
+
+// lerp は a から b へ t の割合だけ進めた値 a + t·(b − a) を最も近い整数に丸める。
+// 差分ではなく補間後の値を丸めるので、半端は向きによらず 0 から遠い側へ丸まる。
+func lerp(a, b int, t float64) int {
+	return int(math.Round(float64(a) + t*float64(b-a)))
+}
+
+// Mirror reflects iv within [0, total), e.g. for right-to-left layouts.
+//
+// Endpoints are swapped as they are negated, so a valid interval stays valid.
+// Mirroring twice with the same total returns iv.
+//
+// For example:
+//
+//	iv = [1,3), total = 10 → [7,9)
+//
+// Mirror(T) = [T − End, T − Start)
+func (iv IntegerInterval) Mirror(total int) IntegerInterval {
+	return IntegerInterval{Start: total - iv.End, End: total - iv.Start}
+}
+
+// Mirror reflects every member within [0, total) and normalizes the result,
+// so the order of the members is reversed.
+//
+// For example:
+//
+//	set = {[0,2), [5,6)}, total = 10
+//	result = {[4,5), [8,10)}
+//
+// Mirror(T) = Normalize({iv.Mirror(T) | iv ∈ set})
+func (set IntervalSet) Mirror(total int) IntervalSet {
+	mirrored := make(IntervalSet, len(set))
+	for i, iv := range set {
+		mirrored[i] = iv.Mirror(total)
+	}
+	return mirrored.Normalize()
+}
+
+// RelativeTo re-expresses iv in the coordinates of parent, i.e. relative to parent.Start.
+//
+// Returns false if iv is not fully within parent.
+//
+// For example:
+//
+//	iv = [12,15), parent = [10,20) → [2,5)
+//	iv = [8,12),  parent = [10,20) → false
+//
+// RelativeTo(P) = [Start − P.Start, End − P.Start), if iv ⊆ P
+func (iv IntegerInterval) RelativeTo(parent IntegerInterval) (IntegerInterval, bool) {
+	if !parent.Covers(iv) {
+		return IntegerInterval{}, false
+	}
+	return IntegerInterval{Start: iv.Start - parent.Start, End: iv.End - parent.Start}, true
+}
+
+// RelativeToAll re-expresses every member fully within parent relative to parent.Start,
+// dropping the members that are not. Order is preserved.
+//
+// For example:
+//
+//	set = {[12,15), [8,12), [19,20)}, parent = [10,20)
+//	result = {[2,5), [9,10)}
+func (set IntervalSet) RelativeToAll(parent IntegerInterval) IntervalSet {
+	var result IntervalSet
+	for _, iv := range set {
+		if relative, ok := iv.RelativeTo(parent); ok {
+			result = append(result, relative)
+		}
+	}
+	return result
+}
+
+// AbsoluteIn converts iv, expressed relative to parent.Start, back into absolute coordinates.
+// It is the inverse of RelativeTo.
+//
+// The result is not clipped: a relative interval extending past parent's length
+// maps to an absolute interval extending past parent.End.
+//
+// For example:
+//
+//	iv = [2,5), parent = [10,20) → [12,15)
+//
+// AbsoluteIn(P) = [Start + P.Start, End + P.Start)
+func (iv IntegerInterval) AbsoluteIn(parent IntegerInterval) IntegerInterval {
+	return IntegerInterval{Start: iv.Start + parent.Start, End: iv.End + parent.Start}
+}
+
+// AbsoluteInAll converts every member from coordinates relative to parent.Start
+// back into absolute coordinates. Order is preserved and nothing is clipped.
+//
+// For example:
+//
+//	set = {[2,5), [9,10)}, parent = [10,20)
+//	result = {[12,15), [19,20)}
+func (set IntervalSet) AbsoluteInAll(parent IntegerInterval) IntervalSet {
+	result := make(IntervalSet, len(set))
+	for i, iv := range set {
+		result[i] = iv.AbsoluteIn(parent)
+	}
+	return result
+}
+
+// CapLength truncates every member longer than maxLen to [Start, Start+maxLen),
+// leaving shorter members unchanged.
+//
+// The result is not normalized: order and overlaps are preserved.
+// A negative maxLen is treated as 0.
+//
+// For example:
+//
+//	set    = {[0,10), [12,14)}, maxLen = 3
+//	result = {[0,3), [12,14)}
+func (set IntervalSet) CapLength(maxLen int) IntervalSet {
+	maxLen = max(maxLen, 0)
+	result := slices.Clone(set)
+	for i, iv := range result {
+		if iv.Length() > maxLen {
+			result[i].End = iv.Start + maxLen
+		}
+	}
+	return result
+}
+
+// EnsureMinLength clips every member to bounds, grows each one shorter than minLen to minLen,
+// and normalizes the result, which may merge intervals that now overlap or touch.
+//
+// Members lying outside bounds, or only touching its edge, are dropped rather than moved into it;
+// an empty member inside bounds is a point and still grows.
+// A short interval grows by extending its End. If that would pass bounds.End, End stops there
+// and Start is moved back instead (but not before bounds.Start), so an interval only ends up
+// shorter than minLen when bounds itself is.
+//
+// For example:
+//
+//	set = {[0,1), [2,3), [20,21)}, minLen = 2, bounds = [0,10)
+//	grown  = {[0,2), [2,4)}
+//	result = {[0,4)}
+func (set IntervalSet) EnsureMinLength(minLen int, bounds IntegerInterval) IntervalSet {
+	grown := make(IntervalSet, 0, len(set))
+	for _, iv := range set {
+		clipped := IntegerInterval{Start: max(iv.Start, bounds.Start), End: min(iv.End, bounds.End)}
+		if !clipped.IsValid() || (clipped.IsEmpty() && !iv.IsEmpty()) {
+			continue
+		}
+		if clipped.Length() < minLen {
+			clipped.End = min(clipped.Start+minLen, bounds.End)
+			clipped.Start = max(min(clipped.Start, clipped.End-minLen), bounds.Start)
+		}
+		grown = append(grown, clipped)
+	}
+	return grown.canonical()
+}
@@ -0,0 +1,405 @@
+package interval
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestIntegerInterval_Snap(t *testing.T) {
+	tests := []struct {
+		iv   IntegerInterval
+		grid int
+		want IntegerInterval
+	}{
+		{IntegerInterval{3, 17}, 10, IntegerInterval{0, 20}},
+		{IntegerInterval{5, 15}, 10, IntegerInterval{10, 20}},   // 中間は切り上げ
+		{IntegerInterval{-5, 4}, 10, IntegerInterval{0, 0}},     // 負の中間も切り上げ
+		{IntegerInterval{6, 8}, 10, IntegerInterval{10, 10}},    // 潰れて空になる
+		{IntegerInterval{-16, -4}, 10, IntegerInterval{-20, 0}}, // 負の座標
+		{IntegerInterval{3, 7}, 0, IntegerInterval{3, 7}},
+	}
+	for _, tt := range tests {
+		if got := tt.iv.Snap(tt.grid); got != tt.want {
+			t.Errorf("%v.Snap(%d) = %v, want %v", tt.iv, tt.grid, got, tt.want)
+		}
+	}
+}
+
+func TestIntervalSet_DuplicatesDedup(t *testing.T) {
+	set := IntervalSet{{0, 2}, {1, 3}, {0, 2}, {2, 4}, {0, 2}, {1, 3}}
+	if got, want := set.Duplicates(), (IntervalSet{{0, 2}, {1, 3}}); !slices.Equal(got, want) {
+		t.Errorf("Duplicates() = %v, want %v", got, want)
+	}
+	if got, want := set.Dedup(), (IntervalSet{{0, 2}, {1, 3}, {2, 4}}); !slices.Equal(got, want) {
+		t.Errorf("Dedup() = %v, want %v", got, want)
+	}
+	if got := (IntervalSet{{0, 2}, {1, 3}, {2, 4}}).Duplicates(); len(got) != 0 {
+		t.Errorf("Duplicates() of overlapping set = %v, want {}", got)
+	}
+}
+
+func TestIntegerInterval_WithStartWithEnd(t *testing.T) {
+	iv := IntegerInterval{2, 5}
+	if got := iv.WithStart(0); got != (IntegerInterval{0, 5}) {
+		t.Errorf("WithStart(0) = %v", got)
+	}
+	if got := iv.WithEnd(iv.End + 1); got != (IntegerInterval{2, 6}) {
+		t.Errorf("WithEnd(6) = %v", got)
+	}
+	if got := iv.WithStart(9); got != (IntegerInterval{9, 5}) {
+		t.Errorf("WithStart(9) = %v, want the unvalidated [9,5)", got)
+	}
+	if iv != (IntegerInterval{2, 5}) {
+		t.Errorf("original modified: %v", iv)
+	}
+}
+
+func TestIntervalSet_Simplify(t *testing.T) {
+	set := IntervalSet{{20, 22}, {0, 2}, {3, 5}, {10, 12}, {23, 25}}
+	tests := []struct {
+		maxCount int
+		want     IntervalSet
+	}{
+		{5, IntervalSet{{0, 2}, {3, 5}, {10, 12}, {20, 22}, {23, 25}}},
+		{4, IntervalSet{{0, 5}, {10, 12}, {20, 22}, {23, 25}}},
+		{2, IntervalSet{{0, 12}, {20, 25}}},
+		{1, IntervalSet{{0, 25}}},
+		{0, nil},
+	}
+	for _, tt := range tests {
+		if got := set.Simplify(tt.maxCount); !slices.Equal(got, tt.want) {
+			t.Errorf("Simplify(%d) = %v, want %v", tt.maxCount, got, tt.want)
+		}
+	}
+	// 空区間は数えず、隙間の橋渡しにも使わない
+	withEmpty := IntervalSet{{0, 2}, {5, 5}, {10, 12}}
+	if got := withEmpty.Simplify(2); !slices.Equal(got, IntervalSet{{0, 2}, {10, 12}}) {
+		t.Errorf("%v.Simplify(2) = %v, want {[0,2), [10,12)}", withEmpty, got)
+	}
+}
+
+func TestIntervalSet_Rebase(t *testing.T) {
+	tests := []struct {
+		set, want IntervalSet
+		offset    int
+	}{
+		{IntervalSet{{9, 10}, {5, 7}}, IntervalSet{{4, 5}, {0, 2}}, 5},
+		{IntervalSet{{0, 2}, {3, 4}}, IntervalSet{{0, 2}, {3, 4}}, 0},
+		{IntervalSet{{-3, -1}}, IntervalSet{{0, 2}}, -3},
+		{nil, nil, 0},
+	}
+	for _, tt := range tests {
+		got, offset := tt.set.Rebase()
+		if !slices.Equal(got, tt.want) || offset != tt.offset {
+			t.Errorf("%v.Rebase() = %v, %d, want %v, %d", tt.set, got, offset, tt.want, tt.offset)
+		}
+	}
+}
+
+func TestIntervalSet_SortedBy(t *testing.T) {
+	set := IntervalSet{{0, 3}, {1, 8}, {4, 5}, {2, 5}}
+	got := set.SortedBy(func(a, b IntegerInterval) bool { return a.End > b.End })
+	if want := (IntervalSet{{1, 8}, {4, 5}, {2, 5}, {0, 3}}); !slices.Equal(got, want) {
+		t.Errorf("SortedBy(End desc) = %v, want %v", got, want)
+	}
+	if want := (IntervalSet{{0, 3}, {1, 8}, {4, 5}, {2, 5}}); !slices.Equal(set, want) {
+		t.Errorf("original modified: %v", set)
+	}
+}
+
+func TestIntervalSet_SplitBy(t *testing.T) {
+	tests := []struct {
+		set, other, want IntervalSet
+	}{
+		{IntervalSet{{0, 10}}, IntervalSet{{3, 7}}, IntervalSet{{0, 3}, {3, 7}, {7, 10}}},
+		{IntervalSet{{0, 10}}, IntervalSet{{0, 4}, {10, 12}}, IntervalSet{{0, 4}, {4, 10}}}, // 端と一致する境界では切らない
+		{IntervalSet{{0, 5}, {8, 12}}, IntervalSet{{4, 9}}, IntervalSet{{0, 4}, {4, 5}, {8, 9}, {9, 12}}},
+		{IntervalSet{{0, 5}}, nil, IntervalSet{{0, 5}}},
+	}
+	for _, tt := range tests {
+		if got := tt.set.SplitBy(tt.other); !slices.Equal(got, tt.want) {
+			t.Errorf("%v.SplitBy(%v) = %v, want %v", tt.set, tt.other, got, tt.want)
+		}
+	}
+}
+
+func TestIntervalSet_ShiftAfter(t *testing.T) {
+	set := IntervalSet{{0, 2}, {3, 7}, {5, 6}, {4, 4}}
+	if got, want := set.ShiftAfter(4, 10), (IntervalSet{{0, 2}, {3, 7}, {15, 16}, {14, 14}}); !slices.Equal(got, want) {
+		t.Errorf("ShiftAfter(4, 10) = %v, want %v", got, want)
+	}
+	if got, want := set.ShiftAfter(3, -1), (IntervalSet{{0, 2}, {2, 6}, {4, 5}, {3, 3}}); !slices.Equal(got, want) {
+		t.Errorf("ShiftAfter(3, -1) = %v, want %v", got, want)
+	}
+	if set[2] != (IntegerInterval{5, 6}) {
+		t.Errorf("original modified: %v", set)
+	}
+}
+
+func TestIntervalSet_EnforceSeparation(t *testing.T) {
+	tests := []struct {
+		set    IntervalSet
+		minSep int
+		want   IntervalSet
+		shift  int
+	}{
+		{IntervalSet{{3, 5}, {0, 3}}, 2, IntervalSet{{0, 3}, {5, 7}}, 2},
+		// 押し出された区間がさらに次の区間を押す
+		{IntervalSet{{0, 3}, {3, 5}, {6, 8}}, 2, IntervalSet{{0, 3}, {5, 7}, {9, 11}}, 3},
+		{IntervalSet{{0, 3}, {3, 5}, {10, 12}}, 2, IntervalSet{{0, 3}, {5, 7}, {10, 12}}, 0},
+		{IntervalSet{{0, 4}, {2, 5}}, 0, IntervalSet{{0, 4}, {4, 7}}, 2},
+		{IntervalSet{{0, 2}, {5, 7}}, 3, IntervalSet{{0, 2}, {5, 7}}, 0},
+		{nil, 2, IntervalSet{}, 0},
+	}
+	for _, tt := range tests {
+		got, shift := tt.set.EnforceSeparation(tt.minSep)
+		if !slices.Equal(got, tt.want) || shift != tt.shift {
+			t.Errorf("%v.EnforceSeparation(%d) = %v, %d, want %v, %d", tt.set, tt.minSep, got, shift, tt.want, tt.shift)
+		}
+	}
+}
+
+func TestIntervalSet_Downsample(t *testing.T) {
+	set := IntervalSet{{0, 3}, {12, 14}, {15, 16}, {-2, -1}}
+	tests := []struct {
+		cellSize    int
+		minFraction float64
+		want        IntervalSet
+	}{
+		{4, 0, IntervalSet{{-4, 4}, {12, 16}}},
+		// [12,16) は 3/4、[-4,0) は 1/4 しか覆われていない
+		{4, 0.5, IntervalSet{{0, 4}, {12, 16}}},
+		{4, 0.8, nil},
+		{1, 1, IntervalSet{{-2, -1}, {0, 3}, {12, 14}, {15, 16}}},
+		{0, 0, nil},
+	}
+	for _, tt := range tests {
+		if got := set.Downsample(tt.cellSize, tt.minFraction); !slices.Equal(got, tt.want) {
+			t.Errorf("Downsample(%d, %v) = %v, want %v", tt.cellSize, tt.minFraction, got, tt.want)
+		}
+	}
+}
+
+func TestIntervalSet_DilateToStable(t *testing.T) {
+	set := IntervalSet{{20, 21}, {0, 2}, {5, 6}, {9, 9}}
+	got, passes := set.DilateToStable(2)
+	if want := (IntervalSet{{-2, 8}, {18, 23}}); !slices.Equal(got, want) || passes != 1 {
+		t.Errorf("DilateToStable(2) = %v, %d, want %v, 1", got, passes, want)
+	}
+
+	r := rand.New(rand.NewPCG(45, 46))
+	for range 200 {
+		set, radius := randomSet(r), r.IntN(4)
+		clusters, _ := set.DilateToStable(radius)
+		// radius で侵食してから膨張し直しても変わらない（クラスタ分けは 1 回で確定する）
+		eroded := make(IntervalSet, len(clusters))
+		for i, c := range clusters {
+			eroded[i] = IntegerInterval{c.Start + radius, c.End - radius}
+		}
+		if again, _ := eroded.DilateToStable(radius); !slices.Equal(again, clusters) {
+			t.Fatalf("%v.DilateToStable(%d) = %v, but closing and dilating again gives %v", set, radius, clusters, again)
+		}
+		// 膨張そのものは冪等ではない
+		if radius > 0 && len(clusters) > 0 {
+			if twice, _ := clusters.DilateToStable(radius); slices.Equal(twice, clusters) {
+				t.Fatalf("dilating %v again by %d changed nothing", clusters, radius)
+			}
+		}
+	}
+}
+
+func TestIntervalSet_Edges(t *testing.T) {
+	tests := []struct {
+		set, want IntervalSet
+	}{
+		{IntervalSet{{8, 9}, {2, 6}}, IntervalSet{{2, 3}, {5, 6}, {8, 9}}},
+		{IntervalSet{{0, 2}, {2, 3}}, IntervalSet{{0, 1}, {2, 3}}},
+		{IntervalSet{{4, 6}}, IntervalSet{{4, 5}, {5, 6}}},
+		{IntervalSet{{7, 8}}, IntervalSet{{7, 8}}},
+		{IntervalSet{{3, 3}}, nil},
+	}
+	for _, tt := range tests {
+		if got := tt.set.Edges(); !slices.Equal(got, tt.want) {
+			t.Errorf("%v.Edges() = %v, want %v", tt.set, got, tt.want)
+		}
+	}
+}
+
+func TestIntervalSet_RemoveContained(t *testing.T) {
+	tests := []struct {
+		set, want IntervalSet
+	}{
+		{IntervalSet{{0, 10}, {2, 5}, {12, 14}}, IntervalSet{{0, 10}, {12, 14}}},
+		{IntervalSet{{3, 4}, {2, 6}, {0, 10}}, IntervalSet{{0, 10}}},
+		// 部分的に重なるだけの区間は両方残る
+		{IntervalSet{{5, 9}, {0, 6}, {6, 7}}, IntervalSet{{5, 9}, {0, 6}}},
+		{IntervalSet{{1, 4}, {1, 4}, {0, 2}}, IntervalSet{{1, 4}, {0, 2}}},
+		{IntervalSet{{-5, -1}, {-4, -2}}, IntervalSet{{-5, -1}}},
+		{nil, nil},
+	}
+	for _, tt := range tests {
+		if got := tt.set.RemoveContained(); !slices.Equal(got, tt.want) {
+			t.Errorf("%v.RemoveContained() = %v, want %v", tt.set, got, tt.want)
+		}
+	}
+}
+
+func TestIntervalSet_Outermost(t *testing.T) {
+	tests := []struct {
+		set, want IntervalSet
+	}{
+		// 入れ子の [2,5) は消え、部分的に重なる [8,14) は外側と結合する
+		{IntervalSet{{8, 14}, {0, 10}, {2, 5}, {20, 22}}, IntervalSet{{0, 14}, {20, 22}}},
+		{IntervalSet{{0, 2}, {2, 4}}, IntervalSet{{0, 4}}}, // 接する区間も結合する
+		{IntervalSet{{8, 14}, {0, 10}, {2, 5}, {14, 16}}, IntervalSet{{0, 16}}},
+		{IntervalSet{{1, 9}, {2, 3}, {4, 8}, {5, 6}}, IntervalSet{{1, 9}}},
+		{IntervalSet{{3, 3}}, nil},
+	}
+	for _, tt := range tests {
+		if got := tt.set.Outermost(); !slices.Equal(got, tt.want) {
+			t.Errorf("%v.Outermost() = %v, want %v", tt.set, got, tt.want)
+		}
+	}
+	set := IntervalSet{{0, 10}, {8, 14}, {2, 5}}
+	if got := set.RemoveContained(); !slices.Equal(got, IntervalSet{{0, 10}, {8, 14}}) {
+		t.Errorf("RemoveContained() = %v, want the overlapping members kept as they are", got)
+	}
+}
+
+func TestIntegerInterval_Lerp(t *testing.T) {
+	tests := []struct {
+		iv, other IntegerInterval
+		t         float64
+		want      IntegerInterval
+	}{
+		{IntegerInterval{0, 10}, IntegerInterval{10, 15}, 0, IntegerInterval{0, 10}},
+		{IntegerInterval{0, 10}, IntegerInterval{10, 15}, 1, IntegerInterval{10, 15}},
+		{IntegerInterval{0, 10}, IntegerInterval{10, 15}, 0.5, IntegerInterval{5, 13}},    // 12.5 → 13
+		{IntegerInterval{0, 10}, IntegerInterval{10, 15}, 0.25, IntegerInterval{3, 11}},   // 2.5 → 3, 11.25 → 11
+		{IntegerInterval{1, 5}, IntegerInterval{0, 5}, 0.5, IntegerInterval{1, 5}},        // 減少：0.5 → 1
+		{IntegerInterval{10, 20}, IntegerInterval{5, 9}, 0.5, IntegerInterval{8, 15}},     // 減少：7.5 → 8, 14.5 → 15
+		{IntegerInterval{-3, 1}, IntegerInterval{-2, 2}, 0.5, IntegerInterval{-3, 2}},     // 負：−2.5 → −3, 1.5 → 2
+		{IntegerInterval{-10, -4}, IntegerInterval{-5, -1}, 0.5, IntegerInterval{-8, -3}}, // −7.5 → −8, −2.5 → −3
+		{IntegerInterval{0, 10}, IntegerInterval{10, 15}, 2, IntegerInterval{20, 20}},     // 外挿
+	}
+	for _, tt := range tests {
+		if got := tt.iv.Lerp(tt.other, tt.t); got != tt.want {
+			t.Errorf("%v.Lerp(%v, %v) = %v, want %v", tt.iv, tt.other, tt.t, got, tt.want)
+		}
+	}
+}
+
+func TestIntegerInterval_Mirror(t *testing.T) {
+	iv := IntegerInterval{1, 3}
+	if got := iv.Mirror(10); got != (IntegerInterval{7, 9}) {
+		t.Errorf("Mirror(10) = %v, want [7,9)", got)
+	}
+	if got := iv.Mirror(10).Mirror(10); got != iv {
+		t.Errorf("Mirror twice = %v, want %v", got, iv)
+	}
+}
+
+func TestIntervalSet_Mirror(t *testing.T) {
+	set := IntervalSet{{0, 2}, {5, 6}}
+	got := set.Mirror(10)
+	if want := (IntervalSet{{4, 5}, {8, 10}}); !slices.Equal(got, want) {
+		t.Errorf("Mirror(10) = %v, want %v", got, want)
+	}
+	if back := got.Mirror(10); !slices.Equal(back, set) {
+		t.Errorf("Mirror twice = %v, want %v", back, set)
+	}
+}
+
+func TestIntegerInterval_RelativeTo(t *testing.T) {
+	parent := IntegerInterval{10, 20}
+	if got, ok := (IntegerInterval{12, 15}).RelativeTo(parent); !ok || got != (IntegerInterval{2, 5}) {
+		t.Errorf("RelativeTo = %v, %v, want [2,5)", got, ok)
+	}
+	if _, ok := (IntegerInterval{8, 12}).RelativeTo(parent); ok {
+		t.Error("RelativeTo accepted an interval crossing the parent boundary")
+	}
+	set := IntervalSet{{12, 15}, {8, 12}, {19, 20}}
+	if got, want := set.RelativeToAll(parent), (IntervalSet{{2, 5}, {9, 10}}); !slices.Equal(got, want) {
+		t.Errorf("RelativeToAll = %v, want %v", got, want)
+	}
+}
+
+func TestIntegerInterval_AbsoluteIn(t *testing.T) {
+	parent := IntegerInterval{10, 20}
+	for _, iv := range []IntegerInterval{{10, 20}, {12, 15}, {19, 20}} {
+		relative, _ := iv.RelativeTo(parent)
+		if got := relative.AbsoluteIn(parent); got != iv {
+			t.Errorf("AbsoluteIn(RelativeTo(%v)) = %v", iv, got)
+		}
+	}
+	if got := (IntegerInterval{8, 14}).AbsoluteIn(parent); got != (IntegerInterval{18, 24}) {
+		t.Errorf("AbsoluteIn past parent = %v, want unclipped [18,24)", got)
+	}
+	set := IntervalSet{{12, 15}, {19, 20}}
+	if got := set.RelativeToAll(parent).AbsoluteInAll(parent); !slices.Equal(got, set) {
+		t.Errorf("AbsoluteInAll(RelativeToAll(%v)) = %v", set, got)
+	}
+}
+
+func TestIntervalSet_CapLength(t *testing.T) {
+	set := IntervalSet{{0, 10}, {12, 14}, {2, 6}}
+	if got, want := set.CapLength(3), (IntervalSet{{0, 3}, {12, 14}, {2, 5}}); !slices.Equal(got, want) {
+		t.Errorf("CapLength(3) = %v, want %v", got, want)
+	}
+	if got, want := set.CapLength(-1), (IntervalSet{{0, 0}, {12, 12}, {2, 2}}); !slices.Equal(got, want) {
+		t.Errorf("CapLength(-1) = %v, want %v", got, want)
+	}
+}
+
+func TestIntervalSet_EnsureMinLength(t *testing.T) {
+	bounds := IntegerInterval{0, 10}
+	tests := []struct {
+		set    IntervalSet
+		minLen int
+		want   IntervalSet
+	}{
+		{IntervalSet{{0, 1}, {2, 3}}, 2, IntervalSet{{0, 4}}},
+		{IntervalSet{{0, 1}, {5, 9}}, 3, IntervalSet{{0, 3}, {5, 9}}},
+		{IntervalSet{{9, 10}}, 3, IntervalSet{{7, 10}}}, // bounds.End で止まり Start 側に伸ばす
+		{IntervalSet{{8, 8}}, 20, IntervalSet{{0, 10}}},
+		{IntervalSet{{15, 15}, {20, 21}}, 2, nil}, // bounds の外の区間は持ち込まず捨てる
+		{IntervalSet{{-5, -3}, {10, 12}, {3, 4}}, 2, IntervalSet{{3, 5}}},
+		{IntervalSet{{-3, 1}}, 3, IntervalSet{{0, 3}}}, // 先に切り詰めてから伸ばす
+		{IntervalSet{{2, 2}, {5, 6}}, 0, IntervalSet{{5, 6}}},
+	}
+	for _, tt := range tests {
+		if got := tt.set.EnsureMinLength(tt.minLen, bounds); !slices.Equal(got, tt.want) {
+			t.Errorf("%v.EnsureMinLength(%d) = %v, want %v", tt.set, tt.minLen, got, tt.want)
+		}
+	}
+}
+
+func TestTween(t *testing.T) {
+	frames := Tween(IntervalSet{{0, 2}}, IntervalSet{{0, 8}}, 3)
+	want := []IntervalSet{{{0, 2}}, {{0, 5}}, {{0, 8}}}
+	if !slices.EqualFunc(frames, want, slices.Equal) {
+		t.Errorf("Tween = %v, want %v", frames, want)
+	}
+
+	// 縮む端点も補間後の値を丸める：5.5 → 6
+	frames = Tween(IntervalSet{{0, 8}}, IntervalSet{{0, 3}}, 3)
+	want = []IntervalSet{{{0, 8}}, {{0, 6}}, {{0, 3}}}
+	if !slices.EqualFunc(frames, want, slices.Equal) {
+		t.Errorf("shrinking Tween = %v, want %v", frames, want)
+	}
+
+	// 相手のいない区間は点から現れる
+	frames = Tween(IntervalSet{{0, 4}}, IntervalSet{{10, 14}, {0, 4}}, 3)
+	want = []IntervalSet{{{0, 4}}, {{0, 4}, {10, 12}}, {{0, 4}, {10, 14}}}
+	if !slices.EqualFunc(frames, want, slices.Equal) {
+		t.Errorf("Tween with padding = %v, want %v", frames, want)
+	}
+
+	if got := Tween(IntervalSet{{0, 2}}, IntervalSet{{0, 8}}, 1); len(got) != 1 || !slices.Equal(got[0], IntervalSet{{0, 2}}) {
+		t.Errorf("Tween with 1 frame = %v", got)
+	}
+	if got := Tween(IntervalSet{{0, 2}}, nil, 0); got != nil {
+		t.Errorf("Tween with 0 frames = %v", got)
+	}
+}
@@ -0,0 +1,143 @@
+package interval
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// FitsWithin checks that every interval of the set lies within [0, length).
+//
+// Returns nil if all intervals fit, otherwise an error joining one entry
+// per offending interval (Start < 0 or End > length).
+// This is the precondition of ExtractSlices for a text of that length.
+//
+// FitsWithin(L) = nil ⇔ ∀ iv ∈ set, 0 ≤ Start ∧ End ≤ L
+func (set IntervalSet) FitsWithin(length int) error {
+	var errs []error
+	for i, iv := range set {
+		if iv.Start < 0 || iv.End > length {
+			errs = append(errs, fmt.Errorf("interval %d %v out of range [0,%d)", i, iv, length))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validWithin は FitsWithin に加えて Start > End の区間も拒否する。テキストを切り出す前の検査に使う。
+func (set IntervalSet) validWithin(length int) error {
+	var errs []error
+	for i, iv := range set {
+		if !iv.IsValid() {
+			errs = append(errs, fmt.Errorf("interval %d %v is invalid", i, iv))
+		}
+	}
+	return errors.Join(append(errs, set.FitsWithin(length))...)
+}
+
+// IsContiguousCover reports whether the set, once normalized, is exactly {base}:
+// it covers base with no gaps and nothing outside it.
+//
+// For example:
+//
+//	set = {[0,3), [3,5)}, base = [0,5) → true
+//	set = {[0,2), [3,5)}, base = [0,5) → false
+//	set = {[0,6)},        base = [0,5) → false
+//
+// IsContiguousCover(base) ⇔ Normalize(set) = {base}
+func (set IntervalSet) IsContiguousCover(base IntegerInterval) bool {
+	normalized := set.Normalize()
+	return len(normalized) == 1 && normalized[0].Equal(base)
+}
+
+// CoversAll reports whether the set covers every byte of text and nothing outside it:
+// IsContiguousCover specialized to [0, len(text)).
+//
+// Empty members are ignored, so for an empty text the set must cover nothing.
+//
+// For example:
+//
+//	text = "hello"
+//	set = {[0,2), [2,5)} → true
+//	set = {[0,2), [3,5)} → false
+//	set = {[0,6)}        → false
+func (set IntervalSet) CoversAll(text string) bool {
+	canonical := set.canonical()
+	if len(text) == 0 {
+		return len(canonical) == 0
+	}
+	return len(canonical) == 1 && canonical[0].Equal(IntegerInterval{Start: 0, End: len(text)})
+}
+
+// IsPartitionOf checks that the set exactly partitions base: its members lie within base,
+// do not overlap, and leave no gap. Empty members are ignored.
+//
+// Returns nil for a partition, otherwise an error describing the first problem:
+// an invalid member (Start > End), checked before anything else, or else the first of
+// extends past base, overlap, or gap in positional order.
+//
+// For example:
+//
+//	set = {[0,3), [3,5)}, base = [0,5) → nil
+//	set = {[0,2), [3,5)}, base = [0,5) → gap at [2,3)
+//	set = {[0,3), [2,5)}, base = [0,5) → [0,3) overlaps [2,5)
+//
+// IsPartitionOf(base) = nil ⇔ ⋃set = base ∧ members pairwise disjoint
+func (set IntervalSet) IsPartitionOf(base IntegerInterval) error {
+	for _, iv := range set {
+		if !iv.IsValid() {
+			return fmt.Errorf("invalid interval %v", iv)
+		}
+	}
+	sorted := slices.DeleteFunc(slices.Clone(set), IntegerInterval.IsEmpty)
+	slices.SortFunc(sorted, IntegerInterval.Compare)
+
+	covered := base.Start // ここまでは隙間なく覆われている
+	for i, iv := range sorted {
+		if !base.Covers(iv) {
+			return fmt.Errorf("interval %v extends past base %v", iv, base)
+		}
+		if iv.Start < covered {
+			return fmt.Errorf("interval %v overlaps %v", sorted[i-1], iv)
+		}
+		if iv.Start > covered {
+			return fmt.Errorf("gap at %v", IntegerInterval{Start: covered, End: iv.Start})
+		}
+		covered = iv.End
+	}
+	if covered < base.End {
+		return fmt.Errorf("gap at %v", IntegerInterval{Start: covered, End: base.End})
+	}
+	return nil
+}
+
+// FirstViolation reports the first member that keeps the set from being normalized
+// (sorted, non-empty, neither overlapping nor adjacent), for locating problems in
+// externally built sets. ok is true if a violation was found.
+//
+// reason is one of "invalid interval" (Start > End), "empty interval", "not sorted",
+// "overlap with previous" or "adjacent (mergeable) with previous", and index points at
+// the offending member; the last three compare it with set[index−1].
+//
+// For example:
+//
+//	set = {[0,2), [4,6)} → ok = false
+//	set = {[0,2), [2,6)} → 1, "adjacent (mergeable) with previous", true
+//	set = {[0,2), [1,1)} → 1, "empty interval", true
+func (set IntervalSet) FirstViolation() (index int, reason string, ok bool) {
+	for i, iv := range set {
+		switch {
+		case !iv.IsValid():
+			return i, "invalid interval", true
+		case iv.IsEmpty():
+			return i, "empty interval", true
+		case i == 0:
+		case iv.Start < set[i-1].Start:
+			return i, "not sorted", true
+		case iv.Start < set[i-1].End:
+			return i, "overlap with previous", true
+		case iv.Start == set[i-1].End:
+			return i, "adjacent (mergeable) with previous", true
+		}
+	}
+	return 0, "", false
+}
@@ -0,0 +1,106 @@
+package interval
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIntervalSet_FitsWithin(t *testing.T) {
+	if err := (IntervalSet{{0, 3}, {5, 10}}).FitsWithin(10); err != nil {
+		t.Errorf("FitsWithin(10) = %v, want nil", err)
+	}
+	err := IntervalSet{{0, 3}, {5, 11}, {-1, 2}, {8, 12}}.FitsWithin(10)
+	if err == nil {
+		t.Fatal("FitsWithin(10) = nil, want error")
+	}
+	lines := strings.Split(err.Error(), "\n")
+	if len(lines) != 3 || !strings.Contains(lines[0], "[5,11)") || !strings.Contains(lines[2], "[8,12)") {
+		t.Errorf("FitsWithin(10) = %q", err)
+	}
+}
+
+func TestIntervalSet_IsContiguousCover(t *testing.T) {
+	base := IntegerInterval{0, 5}
+	tests := []struct {
+		set  IntervalSet
+		want bool
+	}{
+		{IntervalSet{{3, 5}, {0, 3}}, true},
+		{IntervalSet{{0, 4}, {1, 5}}, true},
+		{IntervalSet{{0, 2}, {3, 5}}, false},
+		{IntervalSet{{0, 6}}, false},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		if got := tt.set.IsContiguousCover(base); got != tt.want {
+			t.Errorf("%v.IsContiguousCover(%v) = %v, want %v", tt.set, base, got, tt.want)
+		}
+	}
+}
+
+func TestIntervalSet_CoversAll(t *testing.T) {
+	tests := []struct {
+		set  IntervalSet
+		text string
+		want bool
+	}{
+		{IntervalSet{{2, 5}, {0, 2}}, "hello", true},
+		{IntervalSet{{0, 3}, {1, 5}, {4, 4}}, "hello", true},
+		{IntervalSet{{0, 2}, {3, 5}}, "hello", false},
+		{IntervalSet{{0, 6}}, "hello", false},
+		{nil, "hello", false},
+		{nil, "", true},
+		{IntervalSet{{0, 1}}, "", false},
+	}
+	for _, tt := range tests {
+		if got := tt.set.CoversAll(tt.text); got != tt.want {
+			t.Errorf("%v.CoversAll(%q) = %v, want %v", tt.set, tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestIntervalSet_IsPartitionOf(t *testing.T) {
+	base := IntegerInterval{0, 5}
+	tests := []struct {
+		set  IntervalSet
+		want string // 空文字列なら nil を期待
+	}{
+		{IntervalSet{{3, 5}, {0, 3}}, ""},
+		{IntervalSet{{0, 3}, {3, 3}, {3, 5}}, ""},
+		{IntervalSet{{0, 2}, {3, 5}}, "gap at [2,3)"},
+		{IntervalSet{{0, 2}, {2, 4}}, "gap at [4,5)"},
+		{IntervalSet{{0, 3}, {2, 5}}, "interval [0,3) overlaps [2,5)"},
+		{IntervalSet{{0, 3}, {3, 6}}, "interval [3,6) extends past base [0,5)"},
+		{IntervalSet{{0, 5}, {4, 2}}, "invalid interval [4,2)"},
+	}
+	for _, tt := range tests {
+		err := tt.set.IsPartitionOf(base)
+		if (err == nil) != (tt.want == "") || (err != nil && err.Error() != tt.want) {
+			t.Errorf("%v.IsPartitionOf(%v) = %v, want %q", tt.set, base, err, tt.want)
+		}
+	}
+}
+
+func TestIntervalSet_FirstViolation(t *testing.T) {
+	tests := []struct {
+		set    IntervalSet
+		index  int
+		reason string
+		ok     bool
+	}{
+		{IntervalSet{{0, 2}, {4, 6}, {8, 9}}, 0, "", false},
+		{nil, 0, "", false},
+		{IntervalSet{{0, 2}, {5, 3}}, 1, "invalid interval", true},
+		{IntervalSet{{0, 2}, {4, 6}, {7, 7}}, 2, "empty interval", true},
+		{IntervalSet{{4, 6}, {0, 2}}, 1, "not sorted", true},
+		{IntervalSet{{0, 4}, {3, 6}}, 1, "overlap with previous", true},
+		{IntervalSet{{0, 4}, {0, 6}}, 1, "overlap with previous", true},
+		{IntervalSet{{0, 2}, {4, 6}, {6, 8}}, 2, "adjacent (mergeable) with previous", true},
+	}
+	for _, tt := range tests {
+		index, reason, ok := tt.set.FirstViolation()
+		if index != tt.index || reason != tt.reason || ok != tt.ok {
+			t.Errorf("%v.FirstViolation() = %d, %q, %v, want %d, %q, %v", tt.set, index, reason, ok, tt.index, tt.reason, tt.ok)
+		}
+	}
+}